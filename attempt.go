@@ -3,6 +3,7 @@ package conflata
 import (
 	"context"
 	"errors"
+	"strings"
 )
 
 type valueSource interface {
@@ -14,6 +15,11 @@ type valueSource interface {
 type attemptCollector struct {
 	fieldPath string
 	attempts  []AttemptError
+	// transform, when set, rewrites fetched values before they are assigned.
+	transform func(string) (string, error)
+	// label, when set, prefixes attempt identifiers to name the part of the
+	// field being fetched, such as the key of a tls.Certificate.
+	label string
 }
 
 func newAttemptCollector(fieldPath string) *attemptCollector {
@@ -21,19 +27,31 @@ func newAttemptCollector(fieldPath string) *attemptCollector {
 }
 
 func (c *attemptCollector) try(ctx context.Context, src valueSource, assign func(string) error) bool {
+	ctx = withAttemptRecorder(ctx, func(err error) {
+		c.fail(src.Source(), src.Identifier(), err)
+	})
 	raw, err := src.Fetch(ctx)
 	if err != nil {
 		c.fail(src.Source(), src.Identifier(), err)
 		return false
 	}
+	if c.transform != nil {
+		if raw, err = c.transform(raw); err != nil {
+			c.fail(SourceTransform, src.Identifier(), classify(err, ErrDecode))
+			return false
+		}
+	}
 	if err := assign(raw); err != nil {
-		c.fail(SourceDecoder, src.Identifier(), err)
+		c.fail(SourceDecoder, src.Identifier(), classify(err, ErrDecode))
 		return false
 	}
 	return true
 }
 
 func (c *attemptCollector) fail(source ValueSource, identifier string, err error) {
+	if c.label != "" {
+		identifier = strings.TrimSpace(c.label + " " + identifier)
+	}
 	c.attempts = append(c.attempts, AttemptError{
 		Source:     source,
 		Identifier: identifier,
@@ -41,12 +59,39 @@ func (c *attemptCollector) fail(source ValueSource, identifier string, err error
 	})
 }
 
+// lastErr returns the error of the most recent failed attempt.
+func (c *attemptCollector) lastErr() error {
+	if len(c.attempts) == 0 {
+		return nil
+	}
+	return c.attempts[len(c.attempts)-1].Err
+}
+
 func (c *attemptCollector) result() *FieldError {
 	if len(c.attempts) == 0 {
-		c.fail(SourceTag, "", errors.New("no env or provider attempts recorded"))
+		c.fail(SourceTag, "", classify(errors.New("no enabled source"), ErrNotFound))
 	}
 	return &FieldError{
 		FieldPath: c.fieldPath,
 		Attempts:  c.attempts,
 	}
 }
+
+type attemptRecorderKey struct{}
+
+// RecordAttempt notes a failed intermediate attempt, such as a retried provider
+// call, against the field whose value is currently being fetched. The attempt
+// is reported as an AttemptError for that field's source even if a later
+// attempt succeeds. It is a no-op when ctx was not supplied by a Loader.
+func RecordAttempt(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	if record, ok := ctx.Value(attemptRecorderKey{}).(func(error)); ok {
+		record(err)
+	}
+}
+
+func withAttemptRecorder(ctx context.Context, record func(error)) context.Context {
+	return context.WithValue(ctx, attemptRecorderKey{}, record)
+}
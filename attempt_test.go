@@ -57,3 +57,30 @@ func TestAttemptCollectorResultAddsTagErrorWhenEmpty(t *testing.T) {
 		t.Fatalf("expected SourceTag fallback, got %+v", err.Attempts)
 	}
 }
+
+type recordingProvider struct{}
+
+func (recordingProvider) Fetch(ctx context.Context, key string) (string, error) {
+	RecordAttempt(ctx, errors.New("throttled"))
+	return "", errors.New("unavailable")
+}
+
+func TestRecordAttemptReplaysToSharedFields(t *testing.T) {
+	type Config struct {
+		A string `conflata:"provider:shared"`
+		B string `conflata:"provider:shared"`
+	}
+	loader := New(WithProvider("aws", recordingProvider{}))
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+	for _, fieldErr := range group.Fields() {
+		if len(fieldErr.Attempts) != 2 || fieldErr.Attempts[0].Err.Error() != "throttled" {
+			t.Fatalf("expected recorded attempt before final error, got %+v", fieldErr.Attempts)
+		}
+	}
+	RecordAttempt(context.Background(), errors.New("ignored"))
+}
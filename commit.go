@@ -0,0 +1,77 @@
+package conflata
+
+import "reflect"
+
+// CommitPolicy controls when resolved values are written into the struct
+// passed to Load.
+type CommitPolicy int
+
+const (
+	// CommitInPlace assigns values into the target as fields resolve, so a
+	// failed load can leave it partially populated. This is the default.
+	CommitInPlace CommitPolicy = iota
+	// CommitOnSuccess resolves into a detached copy of the target and copies
+	// it back only when every field loaded without error.
+	CommitOnSuccess
+	// CommitUnlessRequired resolves into a detached copy of the target and
+	// copies it back unless a required field failed. Failures on optional
+	// fields are still returned in the ErrorGroup.
+	CommitUnlessRequired
+)
+
+// allows reports whether a load that produced group may be committed.
+func (p CommitPolicy) allows(group *ErrorGroup) bool {
+	switch p {
+	case CommitOnSuccess:
+		return !group.Has()
+	case CommitUnlessRequired:
+		return !group.HasRequired()
+	default:
+		return true
+	}
+}
+
+// detach returns an addressable copy of the struct v in which every exported
+// pointer the loader could write through has been replaced by a fresh
+// allocation, so populating the copy never mutates memory reachable from v.
+// Slices, maps and interfaces are shared because the loader only ever replaces
+// them wholesale.
+func detach(v reflect.Value) reflect.Value {
+	fresh := reflect.New(v.Type()).Elem()
+	fresh.Set(v)
+	detachFields(fresh, make(map[detachedPointer]reflect.Value))
+	return fresh
+}
+
+// detachedPointer identifies an original allocation. The type is part of the
+// key because a struct and its first field share an address.
+type detachedPointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func detachFields(v reflect.Value, seen map[detachedPointer]reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			detachFields(field, seen)
+		case reflect.Pointer:
+			if field.IsNil() || !field.CanSet() {
+				continue
+			}
+			key := detachedPointer{addr: field.Pointer(), typ: field.Type()}
+			if clone, ok := seen[key]; ok {
+				field.Set(clone)
+				continue
+			}
+			clone := reflect.New(field.Type().Elem())
+			seen[key] = clone
+			clone.Elem().Set(field.Elem())
+			if clone.Elem().Kind() == reflect.Struct {
+				detachFields(clone.Elem(), seen)
+			}
+			field.Set(clone)
+		}
+	}
+}
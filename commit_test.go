@@ -0,0 +1,104 @@
+package conflata
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type commitInner struct {
+	Host string `conflata:"env:HOST"`
+}
+
+type commitConfig struct {
+	Name    string `conflata:"env:NAME"`
+	Token   string `conflata:"env:TOKEN"`
+	Port    int    `conflata:"env:PORT optional"`
+	Inner   *commitInner
+	Comment string
+}
+
+func commitEnv(env map[string]string) Option {
+	return WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	})
+}
+
+func TestCommitOnSuccessLeavesTargetUntouchedOnFailure(t *testing.T) {
+	inner := &commitInner{Host: "old-host"}
+	cfg := commitConfig{Name: "old", Inner: inner, Comment: "kept"}
+	loader := New(
+		commitEnv(map[string]string{"NAME": "new", "HOST": "new-host"}),
+		WithCommitPolicy(CommitOnSuccess),
+	)
+	if err := loader.Load(context.Background(), &cfg); err == nil {
+		t.Fatalf("expected missing TOKEN to fail the load")
+	}
+	if cfg.Name != "old" || cfg.Inner != inner || inner.Host != "old-host" {
+		t.Fatalf("expected target to be untouched, got %+v (inner %+v)", cfg, *cfg.Inner)
+	}
+}
+
+func TestCommitOnSuccessCommitsWholeLoad(t *testing.T) {
+	inner := &commitInner{Host: "old-host"}
+	cfg := commitConfig{Inner: inner, Comment: "kept"}
+	loader := New(
+		commitEnv(map[string]string{"NAME": "new", "TOKEN": "t", "HOST": "new-host"}),
+		WithCommitPolicy(CommitOnSuccess),
+	)
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.Name != "new" || cfg.Token != "t" || cfg.Inner.Host != "new-host" || cfg.Comment != "kept" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if inner.Host != "old-host" || cfg.Inner == inner {
+		t.Fatalf("expected the previous nested struct to be replaced, not mutated")
+	}
+}
+
+func TestCommitUnlessRequiredToleratesOptionalFailures(t *testing.T) {
+	env := map[string]string{"NAME": "new", "TOKEN": "t", "HOST": "h", "PORT": "not-a-number"}
+	var cfg commitConfig
+	loader := New(commitEnv(env), WithCommitPolicy(CommitUnlessRequired))
+	err := loader.Load(context.Background(), &cfg)
+	group, ok := err.(*ErrorGroup)
+	if !ok || group.HasRequired() {
+		t.Fatalf("expected an optional failure only, got %v", err)
+	}
+	if cfg.Name != "new" {
+		t.Fatalf("expected load to be committed, got %+v", cfg)
+	}
+
+	delete(env, "TOKEN")
+	cfg = commitConfig{Name: "old"}
+	if err := loader.Load(context.Background(), &cfg); err == nil {
+		t.Fatalf("expected required failure")
+	}
+	if cfg.Name != "old" {
+		t.Fatalf("expected required failure to skip the commit, got %+v", cfg)
+	}
+}
+
+func TestDetachClonesPointersOnce(t *testing.T) {
+	type node struct {
+		Value string
+		Next  *node
+	}
+	type holder struct {
+		A, B *node
+	}
+	shared := &node{Value: "a"}
+	shared.Next = shared
+	original := holder{A: shared, B: shared}
+
+	clone := detach(reflect.ValueOf(original)).Interface().(holder)
+	if clone.A == shared || clone.A != clone.B || clone.A.Next != clone.A {
+		t.Fatalf("expected aliasing and cycles to be preserved in the clone")
+	}
+	clone.A.Value = "changed"
+	if shared.Value != "a" {
+		t.Fatalf("expected clone to be detached from original")
+	}
+}
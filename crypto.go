@@ -0,0 +1,249 @@
+package conflata
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	certificateType    = reflect.TypeOf((*x509.Certificate)(nil))
+	certificatesType   = reflect.TypeOf([]*x509.Certificate(nil))
+	certPoolType       = reflect.TypeOf((*x509.CertPool)(nil))
+	signerType         = reflect.TypeOf((*crypto.Signer)(nil)).Elem()
+	privateKeyType     = reflect.TypeOf((*crypto.PrivateKey)(nil)).Elem()
+	rsaKeyType         = reflect.TypeOf((*rsa.PrivateKey)(nil))
+	ecdsaKeyType       = reflect.TypeOf((*ecdsa.PrivateKey)(nil))
+	ed25519KeyType     = reflect.TypeOf(ed25519.PrivateKey(nil))
+	tlsCertificateType = reflect.TypeOf(tls.Certificate{})
+)
+
+// isPEMType reports whether values of t are decoded from PEM by decodePEM. Both
+// the field type and the type behind a pointer field are recognised.
+func isPEMType(t reflect.Type) bool {
+	switch t {
+	case certificateType, certificateType.Elem(),
+		certificatesType,
+		certPoolType, certPoolType.Elem(),
+		signerType, privateKeyType,
+		rsaKeyType, rsaKeyType.Elem(),
+		ecdsaKeyType, ecdsaKeyType.Elem(),
+		ed25519KeyType,
+		tlsCertificateType, reflect.PointerTo(tlsCertificateType):
+		return true
+	}
+	return false
+}
+
+// isTLSCertificateField reports whether t is tls.Certificate, a pointer to it,
+// or a Secret wrapping either.
+func isTLSCertificateField(t reflect.Type) bool {
+	t = unwrapSecret(t)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == tlsCertificateType
+}
+
+// holdsPrivateKey reports whether values of t contain private key material and
+// must never be printed.
+func holdsPrivateKey(t reflect.Type) bool {
+	switch t {
+	case signerType, privateKeyType,
+		rsaKeyType, rsaKeyType.Elem(),
+		ecdsaKeyType, ecdsaKeyType.Elem(),
+		ed25519KeyType,
+		tlsCertificateType, reflect.PointerTo(tlsCertificateType):
+		return true
+	}
+	return false
+}
+
+// decodePEM decodes PEM material into a value of the PEM type t:
+//
+//   - *x509.Certificate takes the first CERTIFICATE block and
+//     []*x509.Certificate every block, rejecting certificates outside their
+//     validity period at now;
+//   - *x509.CertPool adds every certificate without checking validity;
+//   - crypto.Signer, crypto.PrivateKey, *rsa.PrivateKey, *ecdsa.PrivateKey and
+//     ed25519.PrivateKey parse a PKCS #8, PKCS #1 or SEC 1 private key;
+//   - tls.Certificate pairs the certificate chain with the private key from
+//     the same input, rejecting mismatched keys and an expired leaf.
+//
+// Error messages never include the input.
+func decodePEM(raw string, t reflect.Type, now time.Time) (reflect.Value, error) {
+	if t == reflect.PointerTo(tlsCertificateType) {
+		value, err := decodePEM(raw, t.Elem(), now)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(t.Elem())
+		out.Elem().Set(value)
+		return out, nil
+	}
+	switch t {
+	case certificateType, certificateType.Elem():
+		certs, err := parseCertificates(raw, now)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t == certificateType {
+			return reflect.ValueOf(certs[0]), nil
+		}
+		return reflect.ValueOf(*certs[0]), nil
+	case certificatesType:
+		certs, err := parseCertificates(raw, now)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(certs), nil
+	case certPoolType, certPoolType.Elem():
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(raw)) {
+			return reflect.Value{}, errors.New("pem: no certificates found")
+		}
+		if t == certPoolType {
+			return reflect.ValueOf(pool), nil
+		}
+		return reflect.ValueOf(pool).Elem(), nil
+	case tlsCertificateType:
+		return decodeKeyPair(raw, now)
+	default:
+		return decodePrivateKey(raw, t)
+	}
+}
+
+// decodePEMFormat is the built-in pem decoder for explicit `format:pem` tags.
+func decodePEMFormat(raw string, targetType reflect.Type) (any, error) {
+	if !isPEMType(targetType) {
+		return nil, fmt.Errorf("pem: cannot decode into %s", targetType)
+	}
+	value, err := decodePEM(raw, targetType, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// parseCertificates parses every CERTIFICATE block in raw, skipping other
+// block types.
+func parseCertificates(raw string, now time.Time) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(raw)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("pem: certificate %d: %w", len(certs), err)
+		}
+		if err := checkValidity(cert, now); err != nil {
+			return nil, fmt.Errorf("pem: certificate %d: %w", len(certs), err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("pem: no certificates found")
+	}
+	return certs, nil
+}
+
+func checkValidity(cert *x509.Certificate, now time.Time) error {
+	switch {
+	case now.After(cert.NotAfter):
+		return fmt.Errorf("certificate %q expired at %s", cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339))
+	case now.Before(cert.NotBefore):
+		return fmt.Errorf("certificate %q is not valid before %s", cert.Subject.CommonName, cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func decodeKeyPair(raw string, now time.Time) (reflect.Value, error) {
+	pair, err := tls.X509KeyPair([]byte(raw), []byte(raw))
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("pem: key pair: %w", err)
+	}
+	if err := checkValidity(pair.Leaf, now); err != nil {
+		return reflect.Value{}, fmt.Errorf("pem: key pair: %w", err)
+	}
+	return reflect.ValueOf(pair), nil
+}
+
+func decodePrivateKey(raw string, t reflect.Type) (reflect.Value, error) {
+	var block *pem.Block
+	rest := []byte(raw)
+	for {
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return reflect.Value{}, errors.New("pem: no private key found")
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			break
+		}
+	}
+	if _, encrypted := block.Headers["DEK-Info"]; encrypted || block.Type == "ENCRYPTED PRIVATE KEY" {
+		return reflect.Value{}, errors.New("pem: encrypted private keys are not supported")
+	}
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("pem: private key: %w", err)
+	}
+	value := reflect.ValueOf(key)
+	if t.Kind() == reflect.Struct && value.Kind() == reflect.Pointer && value.Elem().Type() == t {
+		// rsa.PrivateKey or ecdsa.PrivateKey held by value.
+		value = value.Elem()
+	}
+	if !value.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("pem: private key is %s, not %s", value.Type(), t)
+	}
+	return value, nil
+}
+
+// describePEM summarises a certificate or pool for Describe. Key material is
+// masked by the caller.
+func describePEM(value reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case *x509.Certificate:
+		return describeCertificate(v)
+	case x509.Certificate:
+		return describeCertificate(&v)
+	case []*x509.Certificate:
+		parts := make([]string, len(v))
+		for i, cert := range v {
+			parts[i] = describeCertificate(cert)
+		}
+		return "[" + strings.Join(parts, "; ") + "]"
+	default:
+		return "<" + value.Type().String() + ">"
+	}
+}
+
+func describeCertificate(cert *x509.Certificate) string {
+	return fmt.Sprintf("%s (expires %s)", cert.Subject, cert.NotAfter.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,168 @@
+package conflata
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKeyPair returns a self-signed certificate and its PKCS #8 key as PEM.
+func testKeyPair(t *testing.T, name string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestLoaderDecodesPEMMaterial(t *testing.T) {
+	valid := time.Now().Add(24 * time.Hour)
+	certA, keyA := testKeyPair(t, "a.example", valid)
+	certB, _ := testKeyPair(t, "b.example", valid)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	rsaPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+
+	type Config struct {
+		Cert   *x509.Certificate       `conflata:"env:CERT"`
+		Chain  []*x509.Certificate     `conflata:"env:CHAIN"`
+		Roots  *x509.CertPool          `conflata:"env:CHAIN"`
+		Signer crypto.Signer           `conflata:"env:KEY_A"`
+		ECDSA  *ecdsa.PrivateKey       `conflata:"env:KEY_A"`
+		RSA    Secret[*rsa.PrivateKey] `conflata:"env:KEY_RSA"`
+		Pair   tls.Certificate         `conflata:"env:CERT keyenv:KEY_A"`
+		Bundle *tls.Certificate        `conflata:"env:BUNDLE"`
+		Packed tls.Certificate         `conflata:"env:CERT_B64 transform:base64 keyenv:KEY_A"`
+	}
+	env := map[string]string{
+		"CERT":    certA,
+		"CHAIN":   certA + certB,
+		"KEY_A":   keyA,
+		"KEY_RSA": rsaPEM,
+		"BUNDLE":  certA + keyA,
+		// The certificate's transforms do not apply to the key.
+		"CERT_B64": base64.StdEncoding.EncodeToString([]byte(certA)),
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Cert.Subject.CommonName != "a.example" || len(cfg.Chain) != 2 || cfg.Chain[1].Subject.CommonName != "b.example" {
+		t.Fatalf("unexpected certificates %v %v", cfg.Cert.Subject, cfg.Chain)
+	}
+	if cfg.Roots == nil || cfg.Signer == nil || cfg.ECDSA == nil || !cfg.RSA.Reveal().Equal(rsaKey) {
+		t.Fatalf("unexpected pool or keys %+v", cfg)
+	}
+	if cfg.Pair.Leaf == nil || cfg.Pair.Leaf.Subject.CommonName != "a.example" || cfg.Bundle == nil || cfg.Bundle.PrivateKey == nil {
+		t.Fatalf("unexpected key pairs %+v %+v", cfg.Pair, cfg.Bundle)
+	}
+	if entry, _ := report.Field("Pair"); entry.Format != "pem" || entry.Identifier != "CERT" || entry.KeySource != SourceEnv || entry.KeyIdentifier != "KEY_A" {
+		t.Fatalf("expected pem format and key source in report, got %+v", entry)
+	}
+	if cfg.Packed.Leaf == nil || cfg.Packed.Leaf.Subject.CommonName != "a.example" {
+		t.Fatalf("unexpected transformed key pair %+v", cfg.Packed)
+	}
+
+	dump, err := Dump(&cfg)
+	if err != nil {
+		t.Fatalf("Dump error: %v", err)
+	}
+	if !strings.Contains(dump, "Cert = CN=a.example (expires") || !strings.Contains(dump, "Pair = [REDACTED]") || !strings.Contains(dump, "Signer = [REDACTED]") {
+		t.Fatalf("unexpected dump:\n%s", dump)
+	}
+}
+
+func TestLoaderRejectsInvalidPEMMaterial(t *testing.T) {
+	expired, expiredKey := testKeyPair(t, "old.example", time.Now().Add(-time.Hour))
+	cert, _ := testKeyPair(t, "a.example", time.Now().Add(time.Hour))
+	_, otherKey := testKeyPair(t, "b.example", time.Now().Add(time.Hour))
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("marshal ed25519 key: %v", err)
+	}
+	edPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}))
+
+	type Config struct {
+		Expired    *x509.Certificate `conflata:"env:EXPIRED"`
+		ExpiredTLS tls.Certificate   `conflata:"env:EXPIRED keyenv:EXPIRED_KEY"`
+		Mismatch   tls.Certificate   `conflata:"env:CERT keyenv:OTHER_KEY"`
+		MissingKey tls.Certificate   `conflata:"env:CERT keyenv:NO_KEY"`
+		WrongKey   *rsa.PrivateKey   `conflata:"env:OTHER_KEY"`
+		WrongValue rsa.PrivateKey    `conflata:"env:ED_KEY"`
+		NotTLS     string            `conflata:"env:CERT keyenv:OTHER_KEY"`
+	}
+	env := map[string]string{"EXPIRED": expired, "EXPIRED_KEY": expiredKey, "CERT": cert, "OTHER_KEY": otherKey, "ED_KEY": edPEM}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+	var cfg Config
+	err = loader.Load(context.Background(), &cfg)
+	var group *ErrorGroup
+	if !errors.As(err, &group) || len(group.Fields()) != 7 {
+		t.Fatalf("expected seven failures, got %v", err)
+	}
+	for i, want := range []struct {
+		source ValueSource
+		text   string
+	}{
+		{SourceDecoder, `certificate "old.example" expired at`},
+		{SourceDecoder, `certificate "old.example" expired at`},
+		{SourceDecoder, "private key does not match public key"},
+		{SourceEnv, "(key NO_KEY): not set"},
+		{SourceDecoder, "private key is *ecdsa.PrivateKey, not *rsa.PrivateKey"},
+		{SourceDecoder, "private key is ed25519.PrivateKey, not rsa.PrivateKey"},
+		{SourceTag, "require a tls.Certificate field"},
+	} {
+		attempts := group.Fields()[i].Attempts
+		last := attempts[len(attempts)-1]
+		if last.Source != want.source || !strings.Contains(last.Error(), want.text) {
+			t.Fatalf("field %s: expected %s attempt containing %q, got %+v", group.Fields()[i].FieldPath, want.source, want.text, attempts)
+		}
+	}
+	if strings.Contains(err.Error(), "BEGIN") {
+		t.Fatalf("errors leaked PEM content: %v", err)
+	}
+}
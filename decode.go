@@ -16,6 +16,12 @@ var builtinDecoders = map[string]DecodeFunc{
 	"json": decodeJSON,
 	"xml":  decodeXML,
 	"text": decodeTextFormat,
+	"yaml": decodeYAML,
+	"yml":  decodeYAML,
+	"toml": decodeTOML,
+	"csv":  decodeDelimited,
+	"kv":   decodeDelimited,
+	"pem":  decodePEMFormat,
 }
 
 var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
@@ -39,18 +45,65 @@ func decodeXML(raw string, targetType reflect.Type) (any, error) {
 }
 
 func decodeTextFormat(raw string, targetType reflect.Type) (any, error) {
+	return decodePrimitive(raw, targetType)
+}
+
+// decodeUnmarshaler decodes raw through the target type's own UnmarshalText or
+// UnmarshalJSON method. It reports false when the type implements neither.
+// TextUnmarshaler wins because raw values are plain strings; JSON unmarshalers
+// receive raw as-is when it is valid JSON and as a quoted string otherwise.
+func decodeUnmarshaler(raw string, targetType reflect.Type) (any, bool, error) {
 	ptrType := reflect.PointerTo(targetType)
-	if ptrType.Implements(textUnmarshalerType) {
+	switch {
+	case ptrType.Implements(textUnmarshalerType):
 		dest := reflect.New(targetType)
 		if err := dest.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
-			return nil, fmt.Errorf("text decode: %w", err)
+			return nil, true, &unmarshalerError{kind: "text", target: targetType, err: err}
+		}
+		return dest.Elem().Interface(), true, nil
+	case ptrType.Implements(jsonUnmarshalerType):
+		payload := []byte(raw)
+		if !json.Valid(payload) {
+			payload = []byte(strconv.Quote(raw))
+		}
+		dest := reflect.New(targetType)
+		if err := dest.Interface().(json.Unmarshaler).UnmarshalJSON(payload); err != nil {
+			return nil, true, &unmarshalerError{kind: "json", target: targetType, err: err}
 		}
-		return dest.Elem().Interface(), nil
+		return dest.Elem().Interface(), true, nil
+	default:
+		return nil, false, nil
 	}
-	return decodePrimitive(raw, targetType)
+}
+
+// unmarshalerError is a failure returned by a type's own UnmarshalText or
+// UnmarshalJSON method. Its message is the method's, which may quote any part
+// of the input, so redaction replaces it wholesale.
+type unmarshalerError struct {
+	kind   string
+	target reflect.Type
+	err    error
+}
+
+func (e *unmarshalerError) Error() string {
+	return e.kind + " decode: " + e.err.Error()
+}
+
+func (e *unmarshalerError) Unwrap() error {
+	return e.err
+}
+
+// implementsUnmarshaler reports whether values of t decode themselves via
+// UnmarshalText or UnmarshalJSON.
+func implementsUnmarshaler(t reflect.Type) bool {
+	ptrType := reflect.PointerTo(t)
+	return ptrType.Implements(textUnmarshalerType) || ptrType.Implements(jsonUnmarshalerType)
 }
 
 func decodePrimitive(raw string, targetType reflect.Type) (any, error) {
+	if result, ok, err := decodeUnmarshaler(raw, targetType); ok {
+		return result, err
+	}
 	switch targetType.Kind() {
 	case reflect.String:
 		return raw, nil
@@ -93,18 +146,6 @@ func decodePrimitive(raw string, targetType reflect.Type) (any, error) {
 	case reflect.Struct, reflect.Array, reflect.Map, reflect.Interface:
 		return decodeJSON(raw, targetType)
 	default:
-		ptrType := reflect.PointerTo(targetType)
-		switch {
-		case ptrType.Implements(textUnmarshalerType):
-			return decodeTextFormat(raw, targetType)
-		case ptrType.Implements(jsonUnmarshalerType):
-			holder := reflect.New(targetType)
-			if err := holder.Interface().(json.Unmarshaler).UnmarshalJSON([]byte(raw)); err != nil {
-				return nil, fmt.Errorf("json decode: %w", err)
-			}
-			return holder.Elem().Interface(), nil
-		default:
-			return nil, fmt.Errorf("unsupported target type %s", targetType)
-		}
+		return nil, fmt.Errorf("unsupported target type %s", targetType)
 	}
 }
@@ -1,11 +1,45 @@
 package conflata
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
 
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+type testColour struct {
+	Name string
+}
+
+func (c *testColour) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	c.Name = strings.ToUpper(name)
+	return nil
+}
+
 func TestDecodePrimitiveCoversCommonTypes(t *testing.T) {
 	check := func(expected any, raw string, targetType reflect.Type) {
 		t.Helper()
@@ -52,3 +86,79 @@ func TestDecodeXML(t *testing.T) {
 		t.Fatalf("expected hi, got %+v", got)
 	}
 }
+
+func TestDecodePrimitivePrefersUnmarshalers(t *testing.T) {
+	check := func(expected any, raw string) {
+		t.Helper()
+		got, err := decodePrimitive(raw, reflect.TypeOf(expected))
+		if err != nil {
+			t.Fatalf("decodePrimitive(%q) error: %v", raw, err)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v (%T), got %v (%T)", expected, expected, got, got)
+		}
+	}
+	check(testLevel(2), "HIGH")
+	check(slog.LevelWarn, "warn")
+	check(netip.MustParseAddr("10.0.0.1"), "10.0.0.1")
+	check(testColour{Name: "RED"}, "red")
+	check(testColour{Name: "BLUE"}, `"blue"`)
+
+	if _, err := decodePrimitive("medium", reflect.TypeOf(testLevel(0))); err == nil {
+		t.Fatalf("expected UnmarshalText error to surface")
+	}
+}
+
+func TestLoaderDecodesUnmarshalerTypes(t *testing.T) {
+	type Config struct {
+		Level   testLevel     `conflata:"env:LEVEL"`
+		LogLvl  slog.Level    `conflata:"env:LOG_LEVEL"`
+		Addr    netip.Addr    `conflata:"env:ADDR"`
+		Prefix  *netip.Prefix `conflata:"env:PREFIX"`
+		Big     *big.Int      `conflata:"env:BIG"`
+		Started time.Time     `conflata:"env:STARTED"`
+		Colour  testColour    `conflata:"env:COLOUR"`
+		Nested  struct {
+			Since time.Time
+		}
+	}
+	env := map[string]string{
+		"LEVEL":     "low",
+		"LOG_LEVEL": "DEBUG",
+		"ADDR":      "192.168.1.10",
+		"PREFIX":    "10.0.0.0/8",
+		"BIG":       "123456789012345678901234567890",
+		"STARTED":   "2024-05-01T12:00:00Z",
+		"COLOUR":    "green",
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.Level != 1 || cfg.LogLvl != slog.LevelDebug {
+		t.Fatalf("unexpected levels: %v %v", cfg.Level, cfg.LogLvl)
+	}
+	if cfg.Addr != netip.MustParseAddr("192.168.1.10") {
+		t.Fatalf("unexpected addr: %v", cfg.Addr)
+	}
+	if cfg.Prefix == nil || *cfg.Prefix != netip.MustParsePrefix("10.0.0.0/8") {
+		t.Fatalf("unexpected prefix: %v", cfg.Prefix)
+	}
+	want, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if cfg.Big == nil || cfg.Big.Cmp(want) != 0 {
+		t.Fatalf("unexpected big int: %v", cfg.Big)
+	}
+	if !cfg.Started.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected time: %v", cfg.Started)
+	}
+	if cfg.Colour.Name != "GREEN" {
+		t.Fatalf("unexpected colour: %+v", cfg.Colour)
+	}
+	if !cfg.Nested.Since.IsZero() {
+		t.Fatalf("expected untagged time.Time to be left alone, got %v", cfg.Nested.Since)
+	}
+}
@@ -0,0 +1,138 @@
+package conflata
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	defaultListSeparator = ","
+	defaultKVSeparator   = "="
+)
+
+// decodeDelimited is the built-in csv and kv decoder. It splits raw on commas
+// into slice or array elements, or into key=value map entries.
+func decodeDelimited(raw string, targetType reflect.Type) (any, error) {
+	return delimitedDecoder("", "")(raw, targetType)
+}
+
+// delimitedDecoder returns a decoder that splits raw on sep into the elements
+// of a slice or array, or into the entries of a map with keys and values
+// separated by kvsep. Empty separators fall back to "," and "=". Elements,
+// keys, and values are trimmed and decoded like plain-text fields, so
+// []time.Duration and map[string]int work. Errors name the element position;
+// once redacted they never include its content.
+func delimitedDecoder(sep, kvsep string) DecodeFunc {
+	if sep == "" {
+		sep = defaultListSeparator
+	}
+	if kvsep == "" {
+		kvsep = defaultKVSeparator
+	}
+	return func(raw string, targetType reflect.Type) (any, error) {
+		var parts []string
+		if strings.TrimSpace(raw) != "" {
+			parts = strings.Split(raw, sep)
+		}
+		switch targetType.Kind() {
+		case reflect.Slice:
+			out := reflect.MakeSlice(targetType, len(parts), len(parts))
+			if err := decodeElements(parts, out); err != nil {
+				return nil, err
+			}
+			return out.Interface(), nil
+		case reflect.Array:
+			if len(parts) > targetType.Len() {
+				return nil, fmt.Errorf("delimited decode: %d elements do not fit in %s", len(parts), targetType)
+			}
+			out := reflect.New(targetType).Elem()
+			if err := decodeElements(parts, out); err != nil {
+				return nil, err
+			}
+			return out.Interface(), nil
+		case reflect.Map:
+			out := reflect.MakeMapWithSize(targetType, len(parts))
+			for i, part := range parts {
+				rawKey, rawValue, found := strings.Cut(part, kvsep)
+				if !found {
+					return nil, fmt.Errorf("delimited decode: entry %d has no %q separator", i, kvsep)
+				}
+				key, err := decodeElement(strings.TrimSpace(rawKey), targetType.Key())
+				if err != nil {
+					return nil, newElementError(fmt.Sprintf("key %d", i), rawKey, err)
+				}
+				value, err := decodeElement(strings.TrimSpace(rawValue), targetType.Elem())
+				if err != nil {
+					return nil, newElementError(fmt.Sprintf("value %d", i), rawValue, err)
+				}
+				out.SetMapIndex(key, value)
+			}
+			return out.Interface(), nil
+		default:
+			return nil, fmt.Errorf("delimited decode: %s is not a slice, array, or map", targetType)
+		}
+	}
+}
+
+func decodeElements(parts []string, out reflect.Value) error {
+	for i, part := range parts {
+		value, err := decodeElement(strings.TrimSpace(part), out.Type().Elem())
+		if err != nil {
+			return newElementError(fmt.Sprintf("element %d", i), part, err)
+		}
+		out.Index(i).Set(value)
+	}
+	return nil
+}
+
+// elementError is a failure to decode one element, key, or value of a
+// delimited value. It keeps the element's own text so redaction can scrub it;
+// the whole raw value is not what the element decoder quotes.
+type elementError struct {
+	position string
+	raw      string
+	err      error
+}
+
+func newElementError(position, raw string, err error) error {
+	return &elementError{position: position, raw: strings.TrimSpace(raw), err: err}
+}
+
+func (e *elementError) Error() string {
+	return "delimited decode: " + e.position + ": " + e.err.Error()
+}
+
+func (e *elementError) Unwrap() error {
+	return e.err
+}
+
+// decodeElement decodes a single element with the plain-text rules of
+// decodePrimitive, allocating pointer elements.
+func decodeElement(raw string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Pointer {
+		inner, err := decodeElement(raw, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(inner)
+		return ptr, nil
+	}
+	result, err := decodePrimitive(raw, t)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	value := reflect.ValueOf(result)
+	if !value.IsValid() {
+		return reflect.Value{}, errors.New("decoder produced invalid value")
+	}
+	if !value.Type().AssignableTo(t) {
+		if !value.Type().ConvertibleTo(t) {
+			return reflect.Value{}, fmt.Errorf("decoder produced %s, cannot assign to %s", value.Type(), t)
+		}
+		value = value.Convert(t)
+	}
+	return value, nil
+}
@@ -0,0 +1,104 @@
+package conflata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDelimitedDecoder(t *testing.T) {
+	got, err := decodeDelimited(" a, b ,c", reflect.TypeOf([]string(nil)))
+	if err != nil || !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected strings %v (%v)", got, err)
+	}
+	got, err = delimitedDecoder(";", "")("1s;250ms", reflect.TypeOf([]time.Duration(nil)))
+	if err != nil || !reflect.DeepEqual(got, []time.Duration{time.Second, 250 * time.Millisecond}) {
+		t.Fatalf("unexpected durations %v (%v)", got, err)
+	}
+	got, err = decodeDelimited("1,2", reflect.TypeOf([3]*int{}))
+	if ports := got.([3]*int); err != nil || *ports[0] != 1 || *ports[1] != 2 || ports[2] != nil {
+		t.Fatalf("unexpected array %v (%v)", got, err)
+	}
+	got, err = delimitedDecoder("|", ":")("team:core|tier: 1", reflect.TypeOf(map[string]int(nil)))
+	if err == nil || !strings.Contains(err.Error(), "value 0") {
+		t.Fatalf("expected value error, got %v (%v)", got, err)
+	}
+	got, err = delimitedDecoder("|", ":")("team:1|tier: 2", reflect.TypeOf(map[string]int(nil)))
+	if err != nil || !reflect.DeepEqual(got, map[string]int{"team": 1, "tier": 2}) {
+		t.Fatalf("unexpected map %v (%v)", got, err)
+	}
+	if _, err := decodeDelimited("team", reflect.TypeOf(map[string]string(nil))); err == nil {
+		t.Fatal("expected error for entry without separator")
+	}
+	if _, err := decodeDelimited("1,2,3", reflect.TypeOf([2]int{})); err == nil {
+		t.Fatal("expected error for too many array elements")
+	}
+	if got, err := decodeDelimited(" ", reflect.TypeOf([]int(nil))); err != nil || reflect.ValueOf(got).Len() != 0 {
+		t.Fatalf("expected empty slice, got %v (%v)", got, err)
+	}
+}
+
+func TestLoaderDelimitedFields(t *testing.T) {
+	type Config struct {
+		Hosts    []string          `conflata:"env:HOSTS format:csv"`
+		Ports    []int             `conflata:"env:PORTS sep:' '"`
+		Labels   map[string]string `conflata:"env:LABELS format:kv"`
+		Weights  map[string]int    `conflata:"env:WEIGHTS sep:; kvsep:=>"`
+		Timeouts []time.Duration   `conflata:"env:TIMEOUTS format:csv"`
+	}
+	env := map[string]string{
+		"HOSTS":    "a,b,c",
+		"PORTS":    "80 443",
+		"LABELS":   "team=core,tier=1",
+		"WEIGHTS":  "eu=>2;us=>3",
+		"TIMEOUTS": "1s,2m",
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := Config{
+		Hosts:    []string{"a", "b", "c"},
+		Ports:    []int{80, 443},
+		Labels:   map[string]string{"team": "core", "tier": "1"},
+		Weights:  map[string]int{"eu": 2, "us": 3},
+		Timeouts: []time.Duration{time.Second, 2 * time.Minute},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if entry, _ := report.Field("Weights"); entry.Format != "kv" {
+		t.Fatalf("expected separators to imply kv, got %q", entry.Format)
+	}
+
+	env["PORTS"] = "80 hunter2"
+	err = loader.Load(context.Background(), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "element 1") || strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected redacted element error, got %v", err)
+	}
+
+	env["PORTS"] = "80 443"
+	env["TIMEOUTS"] = "1s, hunter2"
+	err = loader.Load(context.Background(), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "element 1") || strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected redacted duration element error, got %v", err)
+	}
+	env["TIMEOUTS"] = "1s"
+	env["WEIGHTS"] = "eu=>hunter2"
+	err = loader.Load(context.Background(), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "value 0") || strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected redacted map value error, got %v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected element cause to remain matchable: %v", err)
+	}
+}
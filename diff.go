@@ -0,0 +1,65 @@
+package conflata
+
+import "reflect"
+
+// changedPaths returns the paths of fields whose values differ between the
+// structs a and b. It follows the traversal rules of Loader.walkStruct, so the
+// paths match those used in ErrorGroup and Report: unexported and
+// `conflata:"-"` fields are ignored, embedded structs are promoted, and nested
+// structs are compared field by field. A nested struct is reported by its own
+// path when it differs only in fields the traversal does not name.
+func changedPaths(a, b reflect.Value, prefix string) []string {
+	var paths []string
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldPath, childPrefix, ok := fieldTraversal(field, prefix)
+		if !ok {
+			continue
+		}
+		tagged := field.Tag.Get("conflata") != ""
+		paths = append(paths, changedFieldPaths(a.Field(i), b.Field(i), fieldPath, childPrefix, tagged)...)
+	}
+	return paths
+}
+
+func changedFieldPaths(a, b reflect.Value, fieldPath, childPrefix string, tagged bool) []string {
+	if !a.CanInterface() {
+		// Embedded unexported structs can only be compared field by field.
+		return changedPaths(a, b, childPrefix)
+	}
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return nil
+	}
+	switch {
+	case isWalkableStruct(a.Type()):
+		if nested := changedPaths(a, b, childPrefix); len(nested) > 0 {
+			return nested
+		}
+	case a.Kind() == reflect.Pointer && isWalkableStruct(a.Type().Elem()) && !selfReferential(a.Type().Elem()):
+		if !a.IsNil() && !b.IsNil() {
+			if nested := changedPaths(a.Elem(), b.Elem(), childPrefix); len(nested) > 0 {
+				return nested
+			}
+		}
+	case !tagged:
+		// Untagged leaves are never populated by the loader.
+		return nil
+	}
+	return []string{fieldPath}
+}
+
+// isWalkableStruct reports whether the loader descends into values of t rather
+// than treating them as leaves.
+func isWalkableStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !implementsUnmarshaler(t) && !isPEMType(t)
+}
+
+// isWalkableField reports whether a field of type t, a struct or pointer to
+// struct, is descended into rather than decoded.
+func isWalkableField(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return isWalkableStruct(t)
+}
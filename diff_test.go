@@ -0,0 +1,34 @@
+package conflata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedPathsFollowsTraversalRules(t *testing.T) {
+	type Base struct {
+		Region string `conflata:"env:REGION"`
+	}
+	type DB struct {
+		URL  string `conflata:"env:DB_URL"`
+		Pool int    `conflata:"env:DB_POOL"`
+	}
+	type Config struct {
+		Base
+		DB      *DB
+		Blob    DB     `conflata:"env:BLOB"`
+		Skipped string `conflata:"-"`
+		Plain   string
+		Cache   *DB
+	}
+	a := Config{Base: Base{Region: "eu"}, DB: &DB{URL: "a", Pool: 1}, Blob: DB{URL: "x"}, Skipped: "1", Plain: "p"}
+	b := Config{Base: Base{Region: "us"}, DB: &DB{URL: "a", Pool: 2}, Blob: DB{URL: "y"}, Skipped: "2", Plain: "q", Cache: &DB{}}
+	got := changedPaths(reflect.ValueOf(a), reflect.ValueOf(b), "")
+	want := []string{"Region", "DB.Pool", "Blob.URL", "Cache"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if paths := changedPaths(reflect.ValueOf(a), reflect.ValueOf(a), ""); len(paths) != 0 {
+		t.Fatalf("expected no changes, got %v", paths)
+	}
+}
@@ -0,0 +1,234 @@
+package conflata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldDescription is one entry produced by Describe. Path matches the paths
+// used in ErrorGroup and Report.
+type FieldDescription struct {
+	Path     string      `json:"path"`
+	Value    string      `json:"value"`
+	Redacted bool        `json:"redacted,omitempty"`
+	Source   ValueSource `json:"source,omitempty"`
+}
+
+// DescribeOption configures Describe, Dump, and DumpJSON.
+type DescribeOption func(*describer)
+
+// DescribeReport uses the Report from LoadWithReport to annotate each field
+// with its source and to mask exactly the fields that were resolved from a
+// provider. Without a report, every field whose tag names a provider key is
+// masked.
+func DescribeReport(report *Report) DescribeOption {
+	return func(d *describer) {
+		d.report = report
+	}
+}
+
+// DescribeProviderValues stops masking provider-resolved fields. Fields tagged
+// `sensitive` and Secret values stay masked.
+func DescribeProviderValues() DescribeOption {
+	return func(d *describer) {
+		d.revealProviders = true
+	}
+}
+
+// Describe lists the effective configuration in cfg, a struct or pointer to
+// struct, in the order Loader.Load visits it. Values are rendered with fmt and
+// replaced by RedactedPlaceholder for fields tagged `sensitive`, Secret
+// fields, fields resolved from a provider (see DescribeReport and
+// DescribeProviderValues), and everything nested inside a masked field. Masked
+// fields holding a zero value are shown empty so missing secrets stand out.
+// Private keys and TLS key pairs are always masked, and certificates are
+// summarised by subject and expiry.
+// Untagged fields outside tagged structs are not loaded by conflata and are
+// left out.
+func Describe(cfg any, opts ...DescribeOption) ([]FieldDescription, error) {
+	value := reflect.ValueOf(cfg)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, errors.New("conflata: Describe expects a struct or pointer to struct")
+	}
+	d := &describer{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.walk(value, "", describeScope{})
+	return d.fields, nil
+}
+
+// Dump renders Describe's output as an indented tree, one field per line:
+//
+//	ServiceName = checkout
+//	Database:
+//	  URL = postgres://db
+//	  Password = [REDACTED]
+func Dump(cfg any, opts ...DescribeOption) (string, error) {
+	fields, err := Describe(cfg, opts...)
+	if err != nil {
+		return "", err
+	}
+	var (
+		b    strings.Builder
+		prev []string
+	)
+	for _, field := range fields {
+		segments := strings.Split(field.Path, ".")
+		parents := segments[:len(segments)-1]
+		common := 0
+		for common < len(parents) && common < len(prev) && parents[common] == prev[common] {
+			common++
+		}
+		for depth := common; depth < len(parents); depth++ {
+			fmt.Fprintf(&b, "%s%s:\n", strings.Repeat("  ", depth), parents[depth])
+		}
+		fmt.Fprintf(&b, "%s%s = %s", strings.Repeat("  ", len(parents)), segments[len(segments)-1], field.Value)
+		if field.Source != "" {
+			fmt.Fprintf(&b, " (%s)", field.Source)
+		}
+		b.WriteByte('\n')
+		prev = parents
+	}
+	return b.String(), nil
+}
+
+// DumpJSON renders Describe's output as a JSON object nested by field path,
+// with every value as a string.
+func DumpJSON(cfg any, opts ...DescribeOption) ([]byte, error) {
+	fields, err := Describe(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	root := make(map[string]any)
+	for _, field := range fields {
+		segments := strings.Split(field.Path, ".")
+		node := root
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := node[segment].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[segment] = child
+			}
+			node = child
+		}
+		node[segments[len(segments)-1]] = field.Value
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+type describer struct {
+	report          *Report
+	revealProviders bool
+	fields          []FieldDescription
+}
+
+// describeScope carries what a nested field inherits from the tagged field
+// that contains it.
+type describeScope struct {
+	tagged bool
+	masked bool
+	source ValueSource
+}
+
+func (d *describer) walk(current reflect.Value, prefix string, scope describeScope) {
+	t := current.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldPath, childPrefix, ok := fieldTraversal(field, prefix)
+		if !ok {
+			continue
+		}
+		fieldValue := current.Field(i)
+		tagValue := field.Tag.Get("conflata")
+		if tag, err := parseFieldTag(tagValue); err == nil && tag.scopeOnly() && !tag.Sensitive {
+			tagValue = ""
+		}
+		if tagValue == "" && !scope.tagged {
+			if nested, ok := describeNested(fieldValue); ok {
+				d.walk(nested, childPrefix, scope)
+			}
+			continue
+		}
+
+		fieldScope := scope
+		if tagValue != "" {
+			tag, _ := parseFieldTag(tagValue)
+			fieldScope.tagged = true
+			fieldScope.masked = scope.masked || tag.Sensitive || d.fromProvider(fieldPath, tag)
+			if entry, ok := d.report.Field(fieldPath); ok {
+				fieldScope.source = entry.Source
+			}
+		}
+		if nested, ok := describeNested(fieldValue); ok {
+			d.walk(nested, childPrefix, fieldScope)
+			continue
+		}
+		d.add(fieldPath, fieldValue, fieldScope)
+	}
+}
+
+// fromProvider reports whether the field should be masked as provider-sourced.
+func (d *describer) fromProvider(path string, tag fieldTag) bool {
+	if d.revealProviders {
+		return false
+	}
+	if d.report == nil {
+		return tag.ProviderKey != ""
+	}
+	entry, ok := d.report.Field(path)
+	return ok && entry.Source == SourceProvider
+}
+
+func (d *describer) add(path string, value reflect.Value, scope describeScope) {
+	if !value.CanInterface() {
+		return
+	}
+	desc := FieldDescription{Path: path, Source: scope.source}
+	masked := scope.masked || isSecretType(value.Type()) || holdsPrivateKey(value.Type())
+	switch {
+	case masked && !value.IsZero():
+		desc.Value = RedactedPlaceholder
+		desc.Redacted = true
+	case masked:
+		desc.Redacted = true
+	case value.Kind() == reflect.Pointer && value.IsNil():
+		desc.Value = "<nil>"
+	case isPEMType(value.Type()):
+		desc.Value = describePEM(value)
+	case value.Kind() == reflect.Pointer:
+		desc.Value = fmt.Sprint(value.Elem().Interface())
+	default:
+		desc.Value = fmt.Sprint(value.Interface())
+	}
+	d.fields = append(d.fields, desc)
+}
+
+// describeNested returns the struct to walk for a struct or non-nil
+// pointer-to-struct field, following the loader's descent rules.
+func describeNested(value reflect.Value) (reflect.Value, bool) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() || selfReferential(value.Type().Elem()) {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	if !isWalkableStruct(value.Type()) {
+		return reflect.Value{}, false
+	}
+	return value, true
+}
+
+func isSecretType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	_, ok := newSecretHolder(t)
+	return ok
+}
@@ -0,0 +1,164 @@
+package conflata
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type dumpCreds struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+type dumpBase struct {
+	Region string `conflata:"env:REGION"`
+}
+
+type dumpConfig struct {
+	dumpBase
+	Name     string         `conflata:"env:NAME"`
+	APIKey   string         `conflata:"env:API_KEY sensitive"`
+	Token    Secret[string] `conflata:"env:TOKEN"`
+	DBURL    string         `conflata:"env:DB_URL provider:db-url"`
+	Creds    dumpCreds      `conflata:"env:CREDS sensitive"`
+	Timeout  time.Duration  `conflata:"env:TIMEOUT default:5s"`
+	Cache    *dumpCreds
+	Internal string
+	Skipped  string `conflata:"-"`
+}
+
+func TestDumpRendersTreeWithMasking(t *testing.T) {
+	cfg := dumpConfig{
+		dumpBase: dumpBase{Region: "eu-west-1"},
+		Name:     "checkout",
+		APIKey:   "k-123",
+		Token:    NewSecret("t-456"),
+		DBURL:    "postgres://db",
+		Creds:    dumpCreds{User: "app", Password: "pw"},
+		Timeout:  5 * time.Second,
+		Internal: "not loaded",
+	}
+	out, err := Dump(&cfg)
+	if err != nil {
+		t.Fatalf("Dump error: %v", err)
+	}
+	want := `Region = eu-west-1
+Name = checkout
+APIKey = [REDACTED]
+Token = [REDACTED]
+DBURL = [REDACTED]
+Creds:
+  User = [REDACTED]
+  Password = [REDACTED]
+Timeout = 5s
+`
+	if out != want {
+		t.Fatalf("unexpected dump:\n%s\nwant:\n%s", out, want)
+	}
+	for _, secret := range []string{"k-123", "t-456", "postgres", "pw", "not loaded"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("dump leaked %q", secret)
+		}
+	}
+}
+
+func TestDescribeUsesReportSources(t *testing.T) {
+	env := map[string]string{"NAME": "checkout", "DB_URL": "postgres://env", "REGION": "eu"}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}),
+		WithDefaultRequirement(Optional),
+	)
+	var cfg dumpConfig
+	report, _ := loader.LoadWithReport(context.Background(), &cfg)
+
+	fields, err := Describe(cfg, DescribeReport(report))
+	if err != nil {
+		t.Fatalf("Describe error: %v", err)
+	}
+	byPath := make(map[string]FieldDescription)
+	for _, field := range fields {
+		byPath[field.Path] = field
+	}
+	if got := byPath["DBURL"]; got.Value != "postgres://env" || got.Source != SourceEnv || got.Redacted {
+		t.Fatalf("expected env-sourced DBURL to be shown, got %+v", got)
+	}
+	if got := byPath["Timeout"]; got.Value != "5s" || got.Source != SourceDefault {
+		t.Fatalf("unexpected Timeout description: %+v", got)
+	}
+	if got := byPath["APIKey"]; !got.Redacted || got.Value != "" {
+		t.Fatalf("expected empty sensitive field to be flagged, got %+v", got)
+	}
+
+	data, err := DumpJSON(cfg, DescribeReport(report))
+	if err != nil {
+		t.Fatalf("DumpJSON error: %v", err)
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if tree["Name"] != "checkout" || tree["Creds"].(map[string]any)["Password"] != "" {
+		t.Fatalf("unexpected JSON dump: %s", data)
+	}
+}
+
+func TestLoadAndDumpFlagOnlyStruct(t *testing.T) {
+	type Creds struct {
+		User     string `conflata:"env:DB_USER"`
+		Password string `conflata:"env:DB_PASSWORD"`
+	}
+	type Config struct {
+		Name    string `conflata:"env:NAME"`
+		Creds   Creds  `conflata:"sensitive"`
+		Replica *Creds `conflata:"envprefix:REPLICA_ sensitive"`
+	}
+	env := map[string]string{
+		"NAME":                "checkout",
+		"DB_USER":             "app",
+		"DB_PASSWORD":         "pw-primary",
+		"REPLICA_DB_USER":     "reader",
+		"REPLICA_DB_PASSWORD": "pw-replica",
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Creds.Password != "pw-primary" || cfg.Replica == nil || cfg.Replica.User != "reader" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	out, err := Dump(&cfg, DescribeReport(report))
+	if err != nil {
+		t.Fatalf("Dump error: %v", err)
+	}
+	want := `Name = checkout (env)
+Creds:
+  User = [REDACTED] (env)
+  Password = [REDACTED] (env)
+Replica:
+  User = [REDACTED] (env)
+  Password = [REDACTED] (env)
+`
+	if out != want {
+		t.Fatalf("unexpected dump:\n%s\nwant:\n%s", out, want)
+	}
+
+	var leaf struct {
+		Token string `conflata:"required"`
+	}
+	err = loader.Load(context.Background(), &leaf)
+	if err == nil || !strings.Contains(err.Error(), "tag must specify env or provider") {
+		t.Fatalf("expected flag-only leaf to be rejected, got %v", err)
+	}
+}
@@ -1,19 +1,52 @@
 package conflata
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// Sentinel errors classify why an attempt failed. Errors returned by Load wrap
+// them, so callers can test with errors.Is regardless of which source or
+// provider produced the failure. Built-in providers map their SDK errors onto
+// these values and custom providers are encouraged to do the same.
+var (
+	ErrNotFound              = errors.New("not found")
+	ErrPermissionDenied      = errors.New("permission denied")
+	ErrTransient             = errors.New("transient failure")
+	ErrProviderNotRegistered = errors.New("provider not registered")
+	ErrEmptyValue            = errors.New("empty value")
+	ErrDecode                = errors.New("decode failed")
+)
+
+// IsTransient is the default transient-error classifier shared by the provider
+// wrappers. It reports whether err matches ErrTransient or has a Timeout() or
+// Temporary() method returning true; context.DeadlineExceeded and network
+// timeouts qualify through Timeout().
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrTransient) {
+		return true
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true
+	}
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
 // ValueSource identifies where a configuration value was attempted to be read
 // from (environment, provider, decoder, tag parsing, etc.).
 type ValueSource string
 
 const (
-	SourceEnv      ValueSource = "env"
-	SourceProvider ValueSource = "provider"
-	SourceDecoder  ValueSource = "decoder"
-	SourceTag      ValueSource = "tag"
+	SourceEnv       ValueSource = "env"
+	SourceProvider  ValueSource = "provider"
+	SourceDecoder   ValueSource = "decoder"
+	SourceTag       ValueSource = "tag"
+	SourceDefault   ValueSource = "default"
+	SourceTransform ValueSource = "transform"
 )
 
 // AttemptError captures metadata about a failed attempt (environment lookup,
@@ -32,11 +65,32 @@ func (a AttemptError) Error() string {
 	return fmt.Sprintf("%s (%s): %v", a.Source, a.Identifier, a.Err)
 }
 
+// Unwrap returns the underlying error.
+func (a AttemptError) Unwrap() error {
+	return a.Err
+}
+
+// MarshalJSON renders the attempt with its error message so reports can be
+// serialised.
+func (a AttemptError) MarshalJSON() ([]byte, error) {
+	var msg string
+	if a.Err != nil {
+		msg = a.Err.Error()
+	}
+	return json.Marshal(struct {
+		Source     ValueSource `json:"source"`
+		Identifier string      `json:"identifier,omitempty"`
+		Error      string      `json:"error"`
+	}{a.Source, a.Identifier, msg})
+}
+
 // FieldError aggregates all failed attempts for a field. When a field cannot be
 // satisfied it may record multiple AttemptErrors that callers can inspect to
-// decide how to handle the failure.
+// decide how to handle the failure. Required reports whether the field was
+// required; failures of optional fields are informational.
 type FieldError struct {
 	FieldPath string
+	Required  bool
 	Attempts  []AttemptError
 }
 
@@ -52,6 +106,16 @@ func (f FieldError) Error() string {
 	return b.String()
 }
 
+// Unwrap returns the individual attempts so errors.Is and errors.As can match
+// any of them.
+func (f FieldError) Unwrap() []error {
+	errs := make([]error, len(f.Attempts))
+	for i, att := range f.Attempts {
+		errs[i] = att
+	}
+	return errs
+}
+
 // ErrorGroup groups field errors discovered during a loader run. The group can
 // be inspected to understand which fields failed and why.
 type ErrorGroup struct {
@@ -80,6 +144,38 @@ func (g *ErrorGroup) Fields() []FieldError {
 	return out
 }
 
+// Unwrap returns the grouped field errors so errors.Is and errors.As can match
+// failures from any field.
+func (g *ErrorGroup) Unwrap() []error {
+	if g == nil {
+		return nil
+	}
+	errs := make([]error, len(g.fields))
+	for i, fieldErr := range g.fields {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// Required returns only the errors for required fields.
+func (g *ErrorGroup) Required() []FieldError {
+	if g == nil {
+		return nil
+	}
+	var out []FieldError
+	for _, fieldErr := range g.fields {
+		if fieldErr.Required {
+			out = append(out, fieldErr)
+		}
+	}
+	return out
+}
+
+// HasRequired reports whether any required field failed to load.
+func (g *ErrorGroup) HasRequired() bool {
+	return len(g.Required()) > 0
+}
+
 // Has reports whether the group contains any field errors.
 func (g *ErrorGroup) Has() bool {
 	return g != nil && len(g.fields) > 0
@@ -97,3 +193,25 @@ func appendFieldError(g **ErrorGroup, field FieldError) {
 	group.fields = append(group.fields, field)
 	*g = group
 }
+
+// classifiedError keeps the message of err while also matching kind with
+// errors.Is.
+type classifiedError struct {
+	err  error
+	kind error
+}
+
+func classify(err, kind error) error {
+	if err == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &classifiedError{err: err, kind: kind}
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
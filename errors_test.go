@@ -1,7 +1,9 @@
 package conflata
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -36,3 +38,61 @@ func TestAttemptErrorString(t *testing.T) {
 		t.Fatalf("unexpected error string: %s", err.Error())
 	}
 }
+
+func TestErrorGroupSupportsErrorsIsAndAs(t *testing.T) {
+	group := &ErrorGroup{}
+	appendFieldError(&group, FieldError{
+		FieldPath: "Token",
+		Attempts: []AttemptError{
+			{Source: SourceEnv, Identifier: "TOKEN", Err: classify(errors.New("not set"), ErrNotFound)},
+			{Source: SourceProvider, Identifier: "aws:token", Err: ErrPermissionDenied},
+		},
+	})
+	var err error = group
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected sentinels to match through the group: %v", err)
+	}
+	if errors.Is(err, ErrDecode) {
+		t.Fatal("did not expect ErrDecode to match")
+	}
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.FieldPath != "Token" {
+		t.Fatalf("expected errors.As to find FieldError, got %+v", fieldErr)
+	}
+	var attempt AttemptError
+	if !errors.As(err, &attempt) || attempt.Identifier != "TOKEN" {
+		t.Fatalf("expected errors.As to find first AttemptError, got %+v", attempt)
+	}
+}
+
+func TestClassifyPreservesMessage(t *testing.T) {
+	err := classify(errors.New("empty secret"), ErrEmptyValue)
+	if err.Error() != "empty secret" || !errors.Is(err, ErrEmptyValue) {
+		t.Fatalf("unexpected classified error %v", err)
+	}
+}
+
+type temporaryError struct{ temporary bool }
+
+func (e temporaryError) Error() string   { return "temporary" }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+func TestIsTransient(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("throttled: %w", ErrTransient), true},
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("fetch: %w", context.DeadlineExceeded), true},
+		{temporaryError{temporary: true}, true},
+		{temporaryError{}, false},
+		{context.Canceled, false},
+		{ErrNotFound, false},
+		{nil, false},
+	} {
+		if got := IsTransient(tc.err); got != tc.want {
+			t.Fatalf("IsTransient(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
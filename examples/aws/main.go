@@ -16,8 +16,8 @@ import (
 
 // Database credentials are nested to demonstrate recursive traversal.
 type DatabaseCredentials struct {
-	Username string `conflata:"env:DB_USERNAME provider:prod/db-username"`
-	Password string `conflata:"env:DB_PASSWORD provider:prod/db-password"`
+	Username string                  `conflata:"env:DB_USERNAME provider:prod/db-username"`
+	Password conflata.Secret[string] `conflata:"env:DB_PASSWORD provider:prod/db-password"`
 }
 
 type DatabaseConfig struct {
@@ -26,9 +26,10 @@ type DatabaseConfig struct {
 	Creds       DatabaseCredentials // fields inside this struct carry their own tags
 }
 
+// Messaging is optional: the pointer stays nil unless one of its fields is set.
 type MessagingConfig struct {
-	BrokerURL string        `conflata:"env:KAFKA_URL provider:prod/kafka-url"`
-	Timeout   time.Duration `conflata:"env:KAFKA_TIMEOUT provider:prod/kafka-timeout"`
+	BrokerURL string        `conflata:"env:KAFKA_URL provider:prod/kafka-url optional"`
+	Timeout   time.Duration `conflata:"env:KAFKA_TIMEOUT provider:prod/kafka-timeout optional"`
 }
 
 type AppConfig struct {
@@ -22,10 +22,10 @@ type CacheSettings struct {
 }
 
 type APISettings struct {
-	BaseURL string        `json:"baseUrl"`
-	Timeout time.Duration `json:"timeout"`
-	Cache   CacheSettings `json:"cache"`
-	Token   string        `conflata:"env:API_TOKEN provider:api/token"`
+	BaseURL string                  `json:"baseUrl"`
+	Timeout time.Duration           `json:"timeout"`
+	Cache   CacheSettings           `json:"cache"`
+	Token   conflata.Secret[string] `conflata:"env:API_TOKEN provider:api/token"`
 }
 
 type Config struct {
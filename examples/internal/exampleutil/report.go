@@ -1,16 +1,18 @@
 package exampleutil
 
 import (
+	"errors"
 	"log"
 
 	"github.com/djbozjr/conflata"
 )
 
-// ReportWarnings logs configuration issues when the provided error is an
-// *conflata.ErrorGroup. It returns true if warnings were emitted.
+// ReportWarnings logs configuration issues for optional fields when the
+// provided error is an *conflata.ErrorGroup. It returns true if the load can
+// proceed, i.e. no required field failed.
 func ReportWarnings(err error) bool {
-	group, ok := err.(*conflata.ErrorGroup)
-	if !ok || group == nil {
+	var group *conflata.ErrorGroup
+	if !errors.As(err, &group) || group.HasRequired() {
 		return false
 	}
 	for _, fieldErr := range group.Fields() {
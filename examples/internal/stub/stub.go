@@ -2,7 +2,7 @@ package stub
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"os"
 
 	"github.com/djbozjr/conflata"
@@ -42,5 +42,5 @@ func (p provider) Fetch(ctx context.Context, key string) (string, error) {
 	if value, ok := p.secrets[key]; ok {
 		return value, nil
 	}
-	return "", errors.New("stub secret not found: " + key)
+	return "", fmt.Errorf("stub secret %q: %w", key, conflata.ErrNotFound)
 }
@@ -0,0 +1,92 @@
+package conflata
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FetchEvent describes a single provider lookup performed on behalf of a field.
+// Shared is true when the value was not fetched for this field but reused from
+// an identical lookup, either earlier in the same Load or in flight for a
+// concurrent Load on the same Loader.
+type FetchEvent struct {
+	Backend  string
+	Key      string
+	Shared   bool
+	Duration time.Duration
+	Err      error
+}
+
+// fetchKey identifies a provider lookup by backend name and decorated key.
+type fetchKey struct {
+	backend string
+	key     string
+}
+
+// fetchResult is the outcome of a provider lookup, including any intermediate
+// attempts the provider recorded with RecordAttempt.
+type fetchResult struct {
+	value    string
+	metadata *ValueMetadata
+	err      error
+	attempts []error
+}
+
+type fetchCall struct {
+	done   chan struct{}
+	result fetchResult
+	// abandoned is set when the call failed after its caller's context
+	// ended. The failure belongs to that caller alone, so joiners retry.
+	abandoned bool
+}
+
+// fetchGroup coalesces concurrent calls for the same key. When retain is set,
+// completed results stay memoised for the lifetime of the group; otherwise a
+// call is forgotten once it returns so later callers fetch afresh.
+type fetchGroup struct {
+	mu     sync.Mutex
+	calls  map[fetchKey]*fetchCall
+	retain bool
+}
+
+func newFetchGroup(retain bool) *fetchGroup {
+	return &fetchGroup{calls: make(map[fetchKey]*fetchCall), retain: retain}
+}
+
+// do runs fn once per key. Callers that join an existing call wait for its
+// result (or their own ctx) and receive shared == true. A call that fails
+// once its own caller's ctx has ended is neither shared nor retained; joiners
+// run fn again under their ctx instead.
+func (g *fetchGroup) do(ctx context.Context, key fetchKey, fn func(context.Context) fetchResult) (fetchResult, bool) {
+	for {
+		g.mu.Lock()
+		if call, ok := g.calls[key]; ok {
+			g.mu.Unlock()
+			select {
+			case <-call.done:
+				if call.abandoned {
+					continue
+				}
+				return call.result, true
+			case <-ctx.Done():
+				return fetchResult{err: ctx.Err()}, true
+			}
+		}
+		call := &fetchCall{done: make(chan struct{})}
+		g.calls[key] = call
+		g.mu.Unlock()
+
+		call.result = fn(ctx)
+		call.abandoned = call.result.err != nil && ctx.Err() != nil
+		if call.abandoned || !g.retain {
+			// Forgotten before done is closed so retrying joiners start a
+			// fresh call rather than finding this one again.
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+		}
+		close(call.done)
+		return call.result, false
+	}
+}
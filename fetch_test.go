@@ -0,0 +1,44 @@
+package conflata
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFetchGroupRetainsResults(t *testing.T) {
+	g := newFetchGroup(true)
+	calls := 0
+	fn := func(context.Context) fetchResult {
+		calls++
+		return fetchResult{err: errors.New("boom")}
+	}
+	key := fetchKey{backend: "aws", key: "secret"}
+	if res, shared := g.do(context.Background(), key, fn); res.err == nil || shared {
+		t.Fatalf("expected first call to run, shared=%v err=%v", shared, res.err)
+	}
+	if res, shared := g.do(context.Background(), key, fn); res.err == nil || !shared {
+		t.Fatalf("expected memoised error, shared=%v err=%v", shared, res.err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one call, got %d", calls)
+	}
+}
+
+func TestFetchGroupForgetsCompletedCalls(t *testing.T) {
+	g := newFetchGroup(false)
+	calls := 0
+	fn := func(context.Context) fetchResult {
+		calls++
+		return fetchResult{value: "value"}
+	}
+	key := fetchKey{backend: "aws", key: "secret"}
+	for i := 0; i < 2; i++ {
+		if res, shared := g.do(context.Background(), key, fn); res.err != nil || shared {
+			t.Fatalf("call %d: shared=%v err=%v", i, shared, res.err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected completed calls to be forgotten, got %d calls", calls)
+	}
+}
@@ -0,0 +1,383 @@
+package conflata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Live holds a configuration value of type T that can be reloaded while other
+// goroutines read it. Every reload populates a fresh T and publishes it
+// atomically, so values returned by Get are never modified afterwards.
+type Live[T any] struct {
+	loader  *Loader
+	current atomic.Pointer[T]
+	// reloadMu serialises reloads. Callbacks run after it is released.
+	reloadMu sync.Mutex
+
+	mu       sync.Mutex
+	onChange []func(Change[T])
+	onError  []func(error)
+	// deps maps provider keys to the field paths resolved from them, as of
+	// the last published full load.
+	deps map[fetchKey][]string
+}
+
+// Change describes a published reload. Paths lists the field paths whose
+// values differ between Old and New, named as in ErrorGroup and Report.
+type Change[T any] struct {
+	Old   *T
+	New   *T
+	Paths []string
+}
+
+// NewLive performs the initial load of T with loader. A reload is published
+// when the loader's CommitPolicy allows it; CommitInPlace is treated as
+// CommitOnSuccess. When the initial load cannot be published NewLive returns a
+// nil Live. Field errors the policy tolerates are returned alongside a usable
+// Live, as Load does.
+func NewLive[T any](ctx context.Context, loader *Loader) (*Live[T], error) {
+	var probe T
+	if reflect.TypeOf(&probe).Elem().Kind() != reflect.Struct {
+		return nil, errors.New("conflata: Live type must be a struct")
+	}
+	live := &Live[T]{loader: loader}
+	value, deps, err := live.load(ctx, nil, nil)
+	if value == nil {
+		return nil, err
+	}
+	live.deps = deps
+	live.current.Store(value)
+	return live, err
+}
+
+// Get returns the most recently published value. Callers must treat it as
+// read-only.
+func (v *Live[T]) Get() *T {
+	return v.current.Load()
+}
+
+// OnChange registers fn to be called after a reload publishes a value that
+// differs from the previous one. Callbacks run synchronously, in registration
+// order, on the goroutine that performed the reload, once the reload has
+// finished; they may register further callbacks or call Reload.
+func (v *Live[T]) OnChange(fn func(Change[T])) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.onChange = append(v.onChange, fn)
+}
+
+// OnError registers fn to be called when a reload fails and the last good value
+// is kept.
+func (v *Live[T]) OnError(fn func(error)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.onError = append(v.onError, fn)
+}
+
+// Reload loads a fresh value and publishes it when the commit policy allows and
+// it differs from the current one, so Get keeps returning the same pointer
+// while nothing changes. On failure the previous value stays in place, OnError
+// callbacks run, and the error is returned. Concurrent reloads are serialised.
+func (v *Live[T]) Reload(ctx context.Context) error {
+	return v.reload(ctx, nil)
+}
+
+// reload performs a full reload when only is nil. Otherwise it re-resolves
+// just the listed field paths on top of a copy of the current value.
+func (v *Live[T]) reload(ctx context.Context, only map[string]bool) error {
+	v.reloadMu.Lock()
+	prev := v.current.Load()
+	next, deps, err := v.load(ctx, prev, only)
+	var paths []string
+	if next != nil {
+		paths = changedPaths(reflect.ValueOf(prev).Elem(), reflect.ValueOf(next).Elem(), "")
+		if len(paths) > 0 {
+			v.current.Store(next)
+		}
+	}
+	v.mu.Lock()
+	if next != nil && only == nil {
+		v.deps = deps
+	}
+	onChange, onError := v.onChange, v.onError
+	v.mu.Unlock()
+	v.reloadMu.Unlock()
+
+	switch {
+	case next == nil:
+		for _, fn := range onError {
+			fn(err)
+		}
+	case len(paths) > 0:
+		change := Change[T]{Old: prev, New: next, Paths: paths}
+		for _, fn := range onChange {
+			fn(change)
+		}
+	}
+	return err
+}
+
+// load populates a fresh T, or a detached copy of base restricted to the paths
+// in only, and returns nil when the result must not be published. Full loads
+// also return the provider dependencies of each field.
+func (v *Live[T]) load(ctx context.Context, base *T, only map[string]bool) (*T, map[fetchKey][]string, error) {
+	next := new(T)
+	req := loadRequest{dependencies: only == nil, only: only}
+	if only != nil {
+		next = detach(reflect.ValueOf(base).Elem()).Addr().Interface().(*T)
+	}
+	out, err := v.loader.load(ctx, next, req)
+	if err != nil {
+		var group *ErrorGroup
+		if !errors.As(err, &group) {
+			return nil, nil, err
+		}
+		policy := v.loader.commitPolicy
+		if policy == CommitInPlace {
+			policy = CommitOnSuccess
+		}
+		if !policy.allows(group) {
+			return nil, nil, err
+		}
+	}
+	var deps map[fetchKey][]string
+	if req.dependencies {
+		deps = make(map[fetchKey][]string)
+		for _, dep := range out.dependencies {
+			deps[dep.key] = append(deps[dep.key], dep.path)
+		}
+	}
+	return next, deps, err
+}
+
+// WatchOption configures what triggers a reload in Live.Watch.
+type WatchOption func(*watchConfig)
+
+type watchConfig struct {
+	interval  time.Duration
+	signals   []os.Signal
+	triggers  []<-chan struct{}
+	providers bool
+}
+
+// WatchInterval reloads every d.
+func WatchInterval(d time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		if d > 0 {
+			c.interval = d
+		}
+	}
+}
+
+// WatchSignals reloads whenever the process receives one of sigs, typically
+// syscall.SIGHUP.
+func WatchSignals(sigs ...os.Signal) WatchOption {
+	return func(c *watchConfig) {
+		c.signals = append(c.signals, sigs...)
+	}
+}
+
+// WatchTrigger reloads whenever a value is received on ch. A closed channel
+// stops triggering.
+func WatchTrigger(ch <-chan struct{}) WatchOption {
+	return func(c *watchConfig) {
+		if ch != nil {
+			c.triggers = append(c.triggers, ch)
+		}
+	}
+}
+
+// WatchProviders subscribes to every registered provider that implements
+// Watcher. When a provider reports a changed key, only the fields resolved from
+// that key (and their nested fields) are re-resolved. Subscriptions cover the
+// keys used by the load that was current when Watch started.
+func WatchProviders() WatchOption {
+	return func(c *watchConfig) {
+		c.providers = true
+	}
+}
+
+// Watch reloads v whenever one of the configured triggers fires until ctx is
+// done, then returns ctx.Err(). Reload failures are reported through OnError
+// and do not stop the watch.
+func (v *Live[T]) Watch(ctx context.Context, opts ...WatchOption) error {
+	var cfg watchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.interval == 0 && len(cfg.signals) == 0 && len(cfg.triggers) == 0 && !cfg.providers {
+		return errors.New("conflata: Watch requires an interval, signal, trigger, or provider watch")
+	}
+
+	// Requests are coalesced: a full reload absorbs pending targeted ones.
+	var (
+		pendingMu sync.Mutex
+		full      bool
+		targeted  = make(map[string]bool)
+		fired     = make(chan struct{}, 1)
+	)
+	request := func(paths []string) {
+		pendingMu.Lock()
+		if paths == nil {
+			full = true
+		}
+		for _, path := range paths {
+			targeted[path] = true
+		}
+		pendingMu.Unlock()
+		select {
+		case fired <- struct{}{}:
+		default:
+		}
+	}
+	notify := func() { request(nil) }
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if cfg.interval > 0 {
+		ticker := time.NewTicker(cfg.interval)
+		defer ticker.Stop()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					notify()
+				}
+			}
+		}()
+	}
+	if len(cfg.signals) > 0 {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, cfg.signals...)
+		defer signal.Stop(sigCh)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-sigCh:
+					notify()
+				}
+			}
+		}()
+	}
+	for _, trigger := range cfg.triggers {
+		wg.Add(1)
+		go func(trigger <-chan struct{}) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-trigger:
+					if !ok {
+						return
+					}
+					notify()
+				}
+			}
+		}(trigger)
+	}
+
+	if cfg.providers {
+		for _, sub := range v.subscribe(ctx) {
+			wg.Add(1)
+			go func(sub subscription) {
+				defer wg.Done()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case key, ok := <-sub.changes:
+						if !ok {
+							return
+						}
+						if paths := sub.paths[key]; len(paths) > 0 {
+							request(paths)
+						}
+					}
+				}
+			}(sub)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-fired:
+			pendingMu.Lock()
+			reloadAll, paths := full, targeted
+			full, targeted = false, make(map[string]bool)
+			pendingMu.Unlock()
+			switch {
+			case reloadAll:
+				_ = v.reload(ctx, nil)
+			case len(paths) > 0:
+				_ = v.reload(ctx, paths)
+			}
+		}
+	}
+}
+
+// subscription is an active Watcher subscription for one backend.
+type subscription struct {
+	changes <-chan string
+	paths   map[string][]string
+}
+
+// subscribe starts a Watch on every registered Watcher backend for the keys
+// the current value depends on. Failures other than errors.ErrUnsupported are
+// reported through OnError.
+func (v *Live[T]) subscribe(ctx context.Context) []subscription {
+	v.mu.Lock()
+	byBackend := make(map[string]map[string][]string)
+	for key, paths := range v.deps {
+		if byBackend[key.backend] == nil {
+			byBackend[key.backend] = make(map[string][]string)
+		}
+		byBackend[key.backend][key.key] = paths
+	}
+	onError := v.onError
+	v.mu.Unlock()
+
+	var subs []subscription
+	for backend, paths := range byBackend {
+		watcher, ok := v.loader.providers[backend].(Watcher)
+		if !ok {
+			continue
+		}
+		keys := make([]string, 0, len(paths))
+		for key := range paths {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		changes, err := watcher.Watch(ctx, keys)
+		if err != nil {
+			if !errors.Is(err, errors.ErrUnsupported) {
+				for _, fn := range onError {
+					fn(fmt.Errorf("conflata: watch %s: %w", backend, err))
+				}
+			}
+			continue
+		}
+		subs = append(subs, subscription{changes: changes, paths: paths})
+	}
+	return subs
+}
@@ -0,0 +1,228 @@
+package conflata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type liveConfig struct {
+	Name  string `conflata:"env:NAME"`
+	Token string `conflata:"env:TOKEN"`
+	Port  int    `conflata:"env:PORT optional"`
+}
+
+type liveEnv struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (e *liveEnv) set(key, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if value == "" {
+		delete(e.values, key)
+		return
+	}
+	e.values[key] = value
+}
+
+func (e *liveEnv) lookup(key string) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	value, ok := e.values[key]
+	return value, ok
+}
+
+func TestLiveReloadPublishesChanges(t *testing.T) {
+	env := &liveEnv{values: map[string]string{"NAME": "svc", "TOKEN": "one"}}
+	live, err := NewLive[liveConfig](context.Background(), New(WithEnvLookup(env.lookup)))
+	if err != nil {
+		t.Fatalf("NewLive error: %v", err)
+	}
+	first := live.Get()
+	var changes []Change[liveConfig]
+	live.OnChange(func(c Change[liveConfig]) { changes = append(changes, c) })
+
+	if err := live.Reload(context.Background()); err != nil || len(changes) != 0 {
+		t.Fatalf("expected unchanged reload to be silent, got %v %v", err, changes)
+	}
+	env.set("TOKEN", "two")
+	if err := live.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload error: %v", err)
+	}
+	if len(changes) != 1 || !reflect.DeepEqual(changes[0].Paths, []string{"Token"}) {
+		t.Fatalf("expected Token change, got %+v", changes)
+	}
+	if changes[0].Old != first || live.Get().Token != "two" || first.Token != "one" {
+		t.Fatalf("expected a new value to be published without mutating the old one")
+	}
+}
+
+func TestLiveKeepsLastGoodValueOnFailure(t *testing.T) {
+	env := &liveEnv{values: map[string]string{"NAME": "svc", "TOKEN": "one"}}
+	live, err := NewLive[liveConfig](context.Background(), New(WithEnvLookup(env.lookup)))
+	if err != nil {
+		t.Fatalf("NewLive error: %v", err)
+	}
+	var reported error
+	live.OnError(func(err error) { reported = err })
+	env.set("TOKEN", "")
+	err = live.Reload(context.Background())
+	if err == nil || !errors.Is(reported, ErrNotFound) {
+		t.Fatalf("expected reload failure to be reported, got %v / %v", err, reported)
+	}
+	if live.Get().Token != "one" {
+		t.Fatalf("expected last good value to be kept, got %+v", live.Get())
+	}
+}
+
+func TestLiveCallbacksMayReenterLive(t *testing.T) {
+	env := &liveEnv{values: map[string]string{"NAME": "svc", "TOKEN": "one"}}
+	live, err := NewLive[liveConfig](context.Background(), New(WithEnvLookup(env.lookup)))
+	if err != nil {
+		t.Fatalf("NewLive error: %v", err)
+	}
+	var (
+		registered bool
+		errs       []error
+		reloaded   error
+	)
+	live.OnChange(func(Change[liveConfig]) {
+		if registered {
+			return
+		}
+		registered = true
+		live.OnError(func(err error) { errs = append(errs, err) })
+		reloaded = live.Reload(context.Background())
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		env.set("TOKEN", "two")
+		done <- live.Reload(context.Background())
+	}()
+	select {
+	case err := <-done:
+		if err != nil || reloaded != nil {
+			t.Fatalf("unexpected reload errors %v / %v", err, reloaded)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback re-entering Live deadlocked")
+	}
+
+	env.set("TOKEN", "")
+	if err := live.Reload(context.Background()); err == nil || len(errs) != 1 {
+		t.Fatalf("expected callback registered from OnChange to run, got %v / %v", err, errs)
+	}
+}
+
+func TestNewLiveFailsWithoutPublishableValue(t *testing.T) {
+	env := &liveEnv{values: map[string]string{}}
+	live, err := NewLive[liveConfig](context.Background(), New(WithEnvLookup(env.lookup)))
+	if live != nil || err == nil {
+		t.Fatalf("expected initial failure, got %v %v", live, err)
+	}
+}
+
+func TestLiveWatchReloadsOnTrigger(t *testing.T) {
+	env := &liveEnv{values: map[string]string{"NAME": "svc", "TOKEN": "one"}}
+	live, err := NewLive[liveConfig](context.Background(), New(WithEnvLookup(env.lookup)))
+	if err != nil {
+		t.Fatalf("NewLive error: %v", err)
+	}
+	changed := make(chan Change[liveConfig], 1)
+	live.OnChange(func(c Change[liveConfig]) { changed <- c })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	trigger := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- live.Watch(ctx, WatchTrigger(trigger)) }()
+
+	env.set("NAME", "renamed")
+	trigger <- struct{}{}
+	select {
+	case c := <-changed:
+		if c.New.Name != "renamed" {
+			t.Fatalf("unexpected change: %+v", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for reload")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Watch to stop with context.Canceled, got %v", err)
+	}
+	if err := live.Watch(context.Background()); err == nil {
+		t.Fatalf("expected Watch without triggers to fail")
+	}
+}
+
+type watchingProvider struct {
+	mu      sync.Mutex
+	values  map[string]string
+	fetches map[string]int
+	changes chan string
+}
+
+func (w *watchingProvider) Fetch(_ context.Context, key string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.fetches[key]++
+	return w.values[key], nil
+}
+
+func (w *watchingProvider) Watch(context.Context, []string) (<-chan string, error) {
+	return w.changes, nil
+}
+
+func (w *watchingProvider) set(key, value string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.values[key] = value
+}
+
+func (w *watchingProvider) count(key string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.fetches[key]
+}
+
+func TestLiveWatchProvidersReloadsAffectedFields(t *testing.T) {
+	type Config struct {
+		User     string `conflata:"provider:db-user"`
+		Password string `conflata:"provider:db-password"`
+	}
+	provider := &watchingProvider{
+		values:  map[string]string{"db-user": "app", "db-password": "one"},
+		fetches: make(map[string]int),
+		changes: make(chan string),
+	}
+	live, err := NewLive[Config](context.Background(), New(WithProvider("aws", provider)))
+	if err != nil {
+		t.Fatalf("NewLive error: %v", err)
+	}
+	changed := make(chan Change[Config], 1)
+	live.OnChange(func(c Change[Config]) { changed <- c })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go live.Watch(ctx, WatchProviders())
+
+	provider.set("db-password", "two")
+	provider.changes <- "db-password"
+	select {
+	case c := <-changed:
+		if !reflect.DeepEqual(c.Paths, []string{"Password"}) || c.New.Password != "two" || c.New.User != "app" {
+			t.Fatalf("unexpected change: %+v", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for targeted reload")
+	}
+	if provider.count("db-user") != 1 || provider.count("db-password") != 2 {
+		t.Fatalf("expected only the changed key to be refetched, got %v", provider.fetches)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Provider fetches configuration values from an external system such as Vault,
@@ -16,6 +18,32 @@ type Provider interface {
 	Fetch(ctx context.Context, key string) (string, error)
 }
 
+// TransientClassifier is implemented by providers that can tell transient
+// failures (throttling, temporary unavailability) apart from permanent ones.
+// Wrappers such as providers/retry use it to decide whether to try again.
+type TransientClassifier interface {
+	IsTransient(err error) bool
+}
+
+// MetadataProvider is implemented by providers that can describe the version
+// of the value they returned. When available the metadata is included in the
+// Report produced by LoadWithReport.
+type MetadataProvider interface {
+	Provider
+	FetchWithMetadata(ctx context.Context, key string) (string, ValueMetadata, error)
+}
+
+// ValueMetadata carries provider-specific details about a fetched value.
+type ValueMetadata struct {
+	Version    string            `json:"version,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt,omitzero"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+func (m ValueMetadata) isZero() bool {
+	return m.Version == "" && m.CreatedAt.IsZero() && len(m.Attributes) == 0
+}
+
 // EnvLookupFunc describes how to look up environment variables. Override with
 // WithEnvLookup when running in custom environments.
 type EnvLookupFunc func(string) (string, bool)
@@ -23,15 +51,45 @@ type EnvLookupFunc func(string) (string, bool)
 // Loader populates configuration structs from environment variables and
 // external providers according to the struct tags.
 type Loader struct {
-	envLookup       EnvLookupFunc
-	providers       map[string]Provider
-	defaultProvider string
-	defaultFormat   string
-	decoders        map[string]DecodeFunc
-	prefixFunc      func() string
-	suffixFunc      func() string
+	envLookup            EnvLookupFunc
+	providers            map[string]Provider
+	defaultProvider      string
+	defaultFormat        string
+	decoders             map[string]DecodeFunc
+	prefixFunc           func() string
+	suffixFunc           func() string
+	concurrency          int
+	inflight             *fetchGroup
+	fetchHook            func(FetchEvent)
+	defaultRequirement   Requirement
+	commitPolicy         CommitPolicy
+	verboseDecodeErrors  bool
+	sourceOrder          []ValueSource
+	disabledSources      map[ValueSource]bool
+	chains               map[string][]string
+	transientFallthrough bool
+	deprecationHook      func(DeprecationWarning)
+	autoEnv              bool
+	autoEnvPrefix        string
+	envNaming            NamingStrategy
+	transforms           map[string]TransformFunc
 }
 
+// Requirement controls whether a field that cannot be resolved from any
+// source fails the load.
+type Requirement int
+
+const (
+	// Required fields that cannot be resolved are reported in the ErrorGroup
+	// with FieldError.Required set. This is the default.
+	Required Requirement = iota
+	// Optional fields that are not set in any source keep their zero value
+	// (or stay nil) without producing an error. Other failures, such as a
+	// value that cannot be decoded, are still reported with
+	// FieldError.Required unset.
+	Optional
+)
+
 // New constructs a Loader with optional functional options.
 func New(opts ...Option) *Loader {
 	l := &Loader{
@@ -40,10 +98,15 @@ func New(opts ...Option) *Loader {
 		defaultProvider: "aws",
 		defaultFormat:   "json",
 		decoders:        make(map[string]DecodeFunc),
+		sourceOrder:     defaultSourceOrder,
 	}
 	for name, dec := range builtinDecoders {
 		l.decoders[name] = dec
 	}
+	l.transforms = make(map[string]TransformFunc, len(builtinTransforms))
+	for name, fn := range builtinTransforms {
+		l.transforms[name] = fn
+	}
 	for _, opt := range opts {
 		opt(l)
 	}
@@ -55,159 +118,605 @@ func New(opts ...Option) *Loader {
 // can be inspected for per-field failures. Other fatal errors (such as passing
 // a non-struct pointer) are returned directly.
 func (l *Loader) Load(ctx context.Context, target any) error {
+	_, err := l.load(ctx, target, loadRequest{})
+	return err
+}
+
+// LoadWithReport behaves like Load and additionally returns a Report describing
+// where every resolved field came from. The report is returned even when the
+// error is an *ErrorGroup so partially loaded configuration can be inspected.
+func (l *Loader) LoadWithReport(ctx context.Context, target any) (*Report, error) {
+	out, err := l.load(ctx, target, loadRequest{report: true})
+	if out == nil {
+		return nil, err
+	}
+	return &Report{Fields: out.reports}, err
+}
+
+// loadRequest selects what a load collects and which fields it resolves.
+type loadRequest struct {
+	report       bool
+	dependencies bool
+	// only restricts resolution to the tagged fields with these paths and
+	// their descendants. Other fields keep their current values.
+	only map[string]bool
+}
+
+// load walks target and returns the collected results. The walkResult is nil
+// when target itself is invalid.
+func (l *Loader) load(ctx context.Context, target any, req loadRequest) (*walkResult, error) {
 	if target == nil {
-		return errors.New("conflata: target cannot be nil")
+		return nil, errors.New("conflata: target cannot be nil")
 	}
 	value := reflect.ValueOf(target)
 	if value.Kind() != reflect.Pointer || value.IsNil() {
-		return errors.New("conflata: target must be a non-nil pointer")
+		return nil, errors.New("conflata: target must be a non-nil pointer")
 	}
 	elem := value.Elem()
 	if elem.Kind() != reflect.Struct {
-		return errors.New("conflata: target must point to a struct")
+		return nil, errors.New("conflata: target must point to a struct")
 	}
-	var group *ErrorGroup
-	l.walkStruct(ctx, elem, "", &group)
-	if group != nil && group.Has() {
-		return group
+	dest := elem
+	if l.commitPolicy != CommitInPlace {
+		elem = detach(dest)
 	}
-	return nil
+	run := l.newLoadRun()
+	run.report = req.report
+	run.dependencies = req.dependencies
+	run.only = req.only
+	out := &walkResult{}
+	l.walkStruct(ctx, run, elem, walkScope{}, out)
+	if l.commitPolicy != CommitInPlace && l.commitPolicy.allows(out.group) {
+		dest.Set(elem)
+	}
+	if out.group.Has() {
+		return out, out.group
+	}
+	return out, nil
 }
 
-func (l *Loader) walkStruct(ctx context.Context, current reflect.Value, prefix string, group **ErrorGroup) {
-	t := current.Type()
-	for i := 0; i < current.NumField(); i++ {
-		field := t.Field(i)
-		if !field.IsExported() {
-			continue
-		}
-		fieldValue := current.Field(i)
-		fieldPath := field.Name
-		if prefix != "" {
-			fieldPath = prefix + "." + fieldPath
+// loadRun holds state scoped to a single Load call.
+type loadRun struct {
+	// sem bounds the number of fields resolved concurrently. It is nil when
+	// fields are resolved sequentially.
+	sem chan struct{}
+	// fetches memoises provider lookups so identical backend/key pairs are
+	// fetched once per Load.
+	fetches *fetchGroup
+	// report enables collection of FieldReports.
+	report bool
+	// dependencies enables collection of the provider keys each field uses.
+	dependencies bool
+	// only, when set, limits resolution to the listed field paths.
+	only map[string]bool
+}
+
+// walkResult accumulates per-field outcomes in traversal order.
+type walkResult struct {
+	group        *ErrorGroup
+	reports      []FieldReport
+	dependencies []fieldDependency
+}
+
+func (r *walkResult) merge(other walkResult) {
+	if other.group != nil {
+		for _, fieldErr := range other.group.fields {
+			appendFieldError(&r.group, fieldErr)
 		}
-		tagValue := field.Tag.Get("conflata")
-		if tagValue == "" {
-			continue
+	}
+	r.reports = append(r.reports, other.reports...)
+	r.dependencies = append(r.dependencies, other.dependencies...)
+}
+
+func (l *Loader) newLoadRun() *loadRun {
+	run := &loadRun{fetches: newFetchGroup(true)}
+	if l.concurrency > 1 {
+		run.sem = make(chan struct{}, l.concurrency)
+	}
+	return run
+}
+
+// walkScope is what a field inherits from the structs that contain it: its path
+// prefix and the key prefixes set by `envprefix:` and `providerprefix:`.
+type walkScope struct {
+	path           string
+	envPrefix      string
+	providerPrefix string
+}
+
+func (l *Loader) walkStruct(ctx context.Context, run *loadRun, current reflect.Value, scope walkScope, out *walkResult) bool {
+	t := current.Type()
+	if run.sem == nil {
+		assignedAny := false
+		for i := 0; i < current.NumField(); i++ {
+			if l.visitField(ctx, run, t.Field(i), current.Field(i), scope, out) {
+				assignedAny = true
+			}
 		}
-		tag, err := parseFieldTag(tagValue)
-		if err != nil {
-			appendFieldError(group, FieldError{
-				FieldPath: fieldPath,
-				Attempts: []AttemptError{{
-					Source: SourceTag,
-					Err:    err,
-				}},
-			})
-			continue
+		return assignedAny
+	}
+
+	// Each field records into its own slot so the merged results keep
+	// declaration order regardless of which fetch finishes first.
+	var (
+		wg       sync.WaitGroup
+		slots    = make([]walkResult, current.NumField())
+		assigned = make([]bool, current.NumField())
+	)
+	for i := 0; i < current.NumField(); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assigned[i] = l.visitField(ctx, run, t.Field(i), current.Field(i), scope, &slots[i])
+		}(i)
+	}
+	wg.Wait()
+	assignedAny := false
+	for i, slot := range slots {
+		out.merge(slot)
+		if assigned[i] {
+			assignedAny = true
 		}
-		if tag.EnvKey == "" && tag.ProviderKey == "" && !tag.HasDefault {
-			appendFieldError(group, FieldError{
-				FieldPath: fieldPath,
-				Attempts: []AttemptError{{
-					Source: SourceTag,
-					Err:    errors.New("tag must specify env or provider"),
-				}},
-			})
-			continue
+	}
+	return assignedAny
+}
+
+func (l *Loader) visitField(ctx context.Context, run *loadRun, field reflect.StructField, fieldValue reflect.Value, scope walkScope, out *walkResult) bool {
+	fieldPath, childPath, ok := fieldTraversal(field, scope.path)
+	if !ok {
+		return false
+	}
+	childScope := scope
+	childScope.path = childPath
+	tagValue := field.Tag.Get("conflata")
+	if tagValue == "" {
+		return l.descendUntagged(ctx, run, fieldValue, childScope, out)
+	}
+	tag, err := parseFieldTag(tagValue)
+	if err != nil {
+		appendFieldError(&out.group, FieldError{
+			FieldPath: fieldPath,
+			Required:  true,
+			Attempts: []AttemptError{{
+				Source: SourceTag,
+				Err:    err,
+			}},
+		})
+		return false
+	}
+	childScope.envPrefix += tag.EnvPrefix
+	childScope.providerPrefix += tag.ProviderPrefix
+	if tag.scopeOnly() || (tag.flagsOnly() && isWalkableField(fieldValue.Type())) {
+		// Only prefixes, or only flags on a struct: the field is a namespace
+		// for its children and is traversed like an untagged struct. Leaves
+		// with only flags fall through to the missing source error below.
+		return l.descendUntagged(ctx, run, fieldValue, childScope, out)
+	}
+	if tag.EnvKey == "" && tag.ProviderKey == "" && !tag.HasDefault {
+		appendFieldError(&out.group, FieldError{
+			FieldPath: fieldPath,
+			Required:  true,
+			Attempts: []AttemptError{{
+				Source: SourceTag,
+				Err:    errors.New("tag must specify env or provider"),
+			}},
+		})
+		return false
+	}
+	tag.applyScope(scope)
+	if l.autoEnv && tag.EnvKey == "" {
+		tag.EnvKey = l.autoEnvKey(fieldPath)
+	}
+	if run.only != nil && !run.only[fieldPath] {
+		// Not targeted: keep the current value but look for targeted
+		// descendants within it.
+		l.descendExisting(ctx, run, fieldValue, childScope, out)
+		return false
+	}
+	required := l.isRequired(tag)
+	if err := run.acquire(ctx); err != nil {
+		appendFieldError(&out.group, FieldError{
+			FieldPath: fieldPath,
+			Required:  required,
+			Attempts: []AttemptError{{
+				Source: SourceTag,
+				Err:    fmt.Errorf("resolution cancelled: %w", err),
+			}},
+		})
+		return false
+	}
+	assigned, fieldErr := l.populateField(ctx, run, fieldValue, fieldPath, tag, out)
+	run.release()
+	if fieldErr != nil {
+		// Optional fields that simply were not set anywhere keep their zero
+		// value; any other failure is still reported.
+		if !required && allNotFound(fieldErr.Attempts) {
+			return false
 		}
-		if assigned, err := l.populateField(ctx, fieldValue, fieldPath, tag); err != nil {
-			appendFieldError(group, *err)
-		} else if assigned {
-			l.descend(ctx, fieldValue, fieldPath, group)
+		fieldErr.Required = required
+		appendFieldError(&out.group, *fieldErr)
+		return false
+	}
+	if assigned {
+		// Children of a re-resolved field are re-resolved too so their
+		// overrides apply on top of the new parent value.
+		l.descend(ctx, run.unrestricted(), fieldValue, childScope, out)
+	}
+	return assigned
+}
+
+// unrestricted returns a view of the run that resolves every field.
+func (r *loadRun) unrestricted() *loadRun {
+	if r.only == nil {
+		return r
+	}
+	full := *r
+	full.only = nil
+	return &full
+}
+
+// fieldTraversal reports whether walkStruct visits field, the path it is
+// reported under, and the prefix for its nested fields. Unexported fields other
+// than embedded structs and fields tagged `conflata:"-"` are skipped; untagged
+// embedded structs promote their fields into the parent path.
+func fieldTraversal(field reflect.StructField, prefix string) (path, childPrefix string, ok bool) {
+	if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+		return "", "", false
+	}
+	tagValue := field.Tag.Get("conflata")
+	if tagValue == "-" {
+		return "", "", false
+	}
+	path = field.Name
+	if prefix != "" {
+		path = prefix + "." + path
+	}
+	childPrefix = path
+	if field.Anonymous && tagValue == "" {
+		childPrefix = prefix
+	}
+	return path, childPrefix, true
+}
+
+func (l *Loader) isRequired(tag fieldTag) bool {
+	switch tag.Requirement {
+	case requirementOptional:
+		return false
+	case requirementRequired:
+		return true
+	default:
+		return l.defaultRequirement == Required
+	}
+}
+
+func allNotFound(attempts []AttemptError) bool {
+	for _, att := range attempts {
+		if !errors.Is(att.Err, ErrNotFound) {
+			return false
 		}
 	}
+	return true
 }
 
-func (l *Loader) descend(ctx context.Context, fieldValue reflect.Value, fieldPath string, group **ErrorGroup) {
+// acquire reserves a resolution slot, blocking until one is free or ctx is done.
+func (r *loadRun) acquire(ctx context.Context) error {
+	if r.sem == nil {
+		return nil
+	}
+	select {
+	case r.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *loadRun) release() {
+	if r.sem != nil {
+		<-r.sem
+	}
+}
+
+func (l *Loader) descend(ctx context.Context, run *loadRun, fieldValue reflect.Value, scope walkScope, out *walkResult) {
+	if isPEMType(fieldValue.Type()) {
+		return
+	}
 	switch fieldValue.Kind() {
 	case reflect.Struct:
-		l.walkStruct(ctx, fieldValue, fieldPath, group)
+		l.walkStruct(ctx, run, fieldValue, scope, out)
 	case reflect.Pointer:
 		elemType := fieldValue.Type().Elem()
 		if elemType.Kind() == reflect.Struct {
 			if fieldValue.IsNil() {
 				fieldValue.Set(reflect.New(elemType))
 			}
-			l.walkStruct(ctx, fieldValue.Elem(), fieldPath, group)
+			l.walkStruct(ctx, run, fieldValue.Elem(), scope, out)
 		}
 	}
 }
 
-func (l *Loader) populateField(ctx context.Context, fieldValue reflect.Value, fieldPath string, tag fieldTag) (bool, *FieldError) {
+// descendExisting walks a struct or non-nil pointer-to-struct field without
+// allocating, so targeted loads can reach nested fields.
+func (l *Loader) descendExisting(ctx context.Context, run *loadRun, fieldValue reflect.Value, scope walkScope, out *walkResult) {
+	switch {
+	case isWalkableStruct(fieldValue.Type()):
+		l.walkStruct(ctx, run, fieldValue, scope, out)
+	case fieldValue.Kind() == reflect.Pointer && !fieldValue.IsNil() && isWalkableStruct(fieldValue.Type().Elem()):
+		l.walkStruct(ctx, run, fieldValue.Elem(), scope, out)
+	}
+}
+
+// descendUntagged walks struct and pointer-to-struct fields that carry no
+// conflata tag. Nil pointers are only allocated when at least one nested field
+// resolved a value, so optional sub-configs stay nil when nothing is set.
+// Types that decode themselves, such as time.Time or netip.Addr, and
+// certificates and keys are leaves.
+func (l *Loader) descendUntagged(ctx context.Context, run *loadRun, fieldValue reflect.Value, scope walkScope, out *walkResult) bool {
+	switch fieldValue.Kind() {
+	case reflect.Struct:
+		if !isWalkableStruct(fieldValue.Type()) {
+			return false
+		}
+		return l.walkStruct(ctx, run, fieldValue, scope, out)
+	case reflect.Pointer:
+		elemType := fieldValue.Type().Elem()
+		if !isWalkableStruct(elemType) {
+			return false
+		}
+		if !fieldValue.IsNil() {
+			return l.walkStruct(ctx, run, fieldValue.Elem(), scope, out)
+		}
+		if !fieldValue.CanSet() || selfReferential(elemType) {
+			return false
+		}
+		fresh := reflect.New(elemType)
+		if !l.walkStruct(ctx, run, fresh.Elem(), scope, out) {
+			return false
+		}
+		fieldValue.Set(fresh)
+		return true
+	}
+	return false
+}
+
+// selfReferential reports whether walking the struct type t could reach a nil
+// pointer to t again through untagged fields. Such types are never allocated
+// implicitly because the traversal would not terminate.
+func selfReferential(t reflect.Type) bool {
+	return reachesType(t, t, make(map[reflect.Type]bool))
+}
+
+func reachesType(current, target reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[current] {
+		return false
+	}
+	seen[current] = true
+	for i := 0; i < current.NumField(); i++ {
+		field := current.Field(i)
+		if field.Tag.Get("conflata") != "" {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+			if ft == target {
+				return true
+			}
+		}
+		if ft.Kind() == reflect.Struct && reachesType(ft, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Loader) populateField(ctx context.Context, run *loadRun, fieldValue reflect.Value, fieldPath string, tag fieldTag, out *walkResult) (bool, *FieldError) {
 	collector := newAttemptCollector(fieldPath)
+	collector.transform = l.pipeline(tag.Transforms)
 	assign := func(raw string) error {
-		return l.assignValue(fieldValue, raw, tag.Format)
+		return l.decodeField(fieldValue, raw, tag.decodeSpec())
+	}
+	var keySrc valueSource
+	if tag.KeyEnv != "" || tag.KeyProvider != "" {
+		// The private key of a tls.Certificate comes from its own sources
+		// and is appended to the certificate PEM before decoding.
+		if !isTLSCertificateField(fieldValue.Type()) {
+			collector.fail(SourceTag, "", errors.New("keyenv and keyprovider require a tls.Certificate field"))
+			return false, collector.result()
+		}
+		keySources := l.sourcesFor(run, tag.keyTag())
+		if run.dependencies {
+			out.dependencies = append(out.dependencies, dependenciesOf(fieldPath, keySources)...)
+		}
+		// The key has its own collector: the certificate's transforms do
+		// not apply to it and its attempts are labelled as the key's.
+		keyCollector := newAttemptCollector(fieldPath)
+		keyCollector.label = "key"
+		var keyPEM string
+		keySrc = l.trySources(ctx, keyCollector, keySources, func(raw string) error {
+			keyPEM = raw
+			return nil
+		})
+		collector.attempts = append(collector.attempts, keyCollector.attempts...)
+		if keySrc == nil {
+			return false, collector.result()
+		}
+		assign = func(raw string) error {
+			return l.decodeField(fieldValue, raw+"\n"+keyPEM, tag.decodeSpec())
+		}
 	}
-	for _, src := range l.sourcesFor(tag) {
+	sources := l.sourcesFor(run, tag)
+	if run.dependencies {
+		out.dependencies = append(out.dependencies, dependenciesOf(fieldPath, sources)...)
+	}
+	src := l.trySources(ctx, collector, sources, assign)
+	if src == nil {
+		return false, collector.result()
+	}
+	deprecation := deprecationOf(fieldPath, src)
+	if deprecation != nil && l.deprecationHook != nil {
+		l.deprecationHook(*deprecation)
+	}
+	if run.report {
+		report := l.newFieldReport(fieldPath, fieldValue.Type(), tag, src.Source(), src.Identifier(), collector.attempts)
+		if describer, ok := src.(reportDescriber); ok {
+			describer.describe(&report)
+		}
+		report.Deprecation = deprecation
+		if keySrc != nil {
+			report.KeySource = keySrc.Source()
+			report.KeyIdentifier = keySrc.Identifier()
+		}
+		out.reports = append(out.reports, report)
+	}
+	return true, nil
+}
+
+// trySources tries sources in order until one is fetched and assigned, and
+// returns it, or nil when all failed. Failures are recorded in collector.
+func (l *Loader) trySources(ctx context.Context, collector *attemptCollector, sources []valueSource, assign func(string) error) valueSource {
+	// chainBroken is set when a provider failed in a way that must not fall
+	// through to the next backend of its chain.
+	var chainBroken bool
+	for _, src := range sources {
 		if src == nil {
 			continue
 		}
+		provider, isProvider := src.(providerSource)
+		if isProvider && provider.chained && chainBroken {
+			continue
+		}
 		if collector.try(ctx, src, assign) {
-			return true, nil
+			return src
 		}
-	}
-	if tag.HasDefault {
-		if err := l.assignValue(fieldValue, tag.DefaultValue, tag.Format); err != nil {
-			collector.fail(SourceTag, "default", fmt.Errorf("default decode: %w", err))
-			return false, collector.result()
+		if isProvider {
+			chainBroken = !l.fallsThrough(collector.lastErr())
 		}
-		return true, nil
 	}
-	return false, collector.result()
+	return nil
+}
+
+// decodeField assigns raw to field, scrubbing raw from any decode error unless
+// WithVerboseDecodeErrors is in effect.
+func (l *Loader) decodeField(field reflect.Value, raw string, spec decodeSpec) error {
+	err := l.assignValue(field, raw, spec)
+	if err != nil && !l.verboseDecodeErrors {
+		return redactDecodeError(err, raw, field.Type())
+	}
+	return err
+}
+
+// decodeSpec holds the tag keys that select a field's decoder.
+type decodeSpec struct {
+	format string
+	// sep and kvsep, when set, select the delimited decoder with these
+	// separators.
+	sep   string
+	kvsep string
 }
 
-func (l *Loader) assignValue(field reflect.Value, raw string, format string) error {
+func (l *Loader) assignValue(field reflect.Value, raw string, spec decodeSpec) error {
+	if isPEMType(field.Type()) && l.resolveFormat(field.Type(), spec) == "pem" {
+		// Handled before pointers are unwrapped: *x509.CertPool and
+		// crypto.Signer are not decoded through the type they point to.
+		value, err := decodePEM(raw, field.Type(), time.Now())
+		if err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
 	targetType := field.Type()
 	ptr := false
 	if targetType.Kind() == reflect.Pointer {
 		ptr = true
 		targetType = targetType.Elem()
 	}
-	resolvedFormat := strings.ToLower(format)
-	if resolvedFormat == "" && l.defaultFormat != "" && needsStructuredFormat(targetType) {
-		resolvedFormat = l.defaultFormat
+	value, err := l.decodeValue(raw, targetType, spec)
+	if err != nil {
+		return err
+	}
+	if ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(targetType))
+		}
+		field.Elem().Set(value)
+	} else {
+		field.Set(value)
 	}
+	return nil
+}
+
+// decodeValue decodes raw into a value assignable to targetType. Secret fields
+// are decoded using the rules of the type they wrap.
+func (l *Loader) decodeValue(raw string, targetType reflect.Type, spec decodeSpec) (reflect.Value, error) {
+	if holder, ok := newSecretHolder(targetType); ok {
+		inner := reflect.New(holder.secretType()).Elem()
+		if err := l.assignValue(inner, raw, spec); err != nil {
+			return reflect.Value{}, err
+		}
+		holder.setSecret(inner)
+		return reflect.ValueOf(holder).Elem(), nil
+	}
+	resolvedFormat := l.resolveFormat(targetType, spec)
 
 	var (
 		result any
 		err    error
 	)
-	if resolvedFormat != "" {
+	switch {
+	case spec.sep != "" || spec.kvsep != "":
+		result, err = delimitedDecoder(spec.sep, spec.kvsep)(raw, targetType)
+	case resolvedFormat != "":
 		decoder, ok := l.decoders[resolvedFormat]
 		if !ok {
-			return fmt.Errorf("unknown format %q", resolvedFormat)
+			return reflect.Value{}, fmt.Errorf("unknown format %q", resolvedFormat)
 		}
 		result, err = decoder(raw, targetType)
-	} else {
+	default:
 		result, err = l.defaultDecode(raw, targetType)
 	}
 	if err != nil {
-		return err
+		return reflect.Value{}, err
 	}
 	value := reflect.ValueOf(result)
 	if !value.IsValid() {
-		return errors.New("decoder produced invalid value")
+		return reflect.Value{}, errors.New("decoder produced invalid value")
 	}
 	if !value.Type().AssignableTo(targetType) {
-		if value.Type().ConvertibleTo(targetType) {
-			value = value.Convert(targetType)
-		} else {
-			return fmt.Errorf("decoder produced %s, cannot assign to %s", value.Type(), targetType)
+		if !value.Type().ConvertibleTo(targetType) {
+			return reflect.Value{}, fmt.Errorf("decoder produced %s, cannot assign to %s", value.Type(), targetType)
 		}
+		value = value.Convert(targetType)
 	}
-	if ptr {
-		if field.IsNil() {
-			field.Set(reflect.New(targetType))
+	return value, nil
+}
+
+// resolveFormat returns the decoder name used for targetType, or "" when the
+// value is decoded by kind. Certificates and keys imply pem, and separator keys
+// imply csv, or kv for maps.
+func (l *Loader) resolveFormat(targetType reflect.Type, spec decodeSpec) string {
+	targetType = unwrapSecret(targetType)
+	resolvedFormat := strings.ToLower(spec.format)
+	if resolvedFormat == "" && isPEMType(targetType) {
+		return "pem"
+	}
+	if resolvedFormat == "" && (spec.sep != "" || spec.kvsep != "") {
+		resolvedFormat = "csv"
+		if targetType.Kind() == reflect.Map {
+			resolvedFormat = "kv"
 		}
-		field.Elem().Set(value)
-	} else {
-		field.Set(value)
 	}
-	return nil
+	if resolvedFormat == "" && l.defaultFormat != "" && needsStructuredFormat(targetType) {
+		resolvedFormat = l.defaultFormat
+	}
+	return resolvedFormat
 }
 
 func (l *Loader) defaultDecode(raw string, targetType reflect.Type) (any, error) {
+	if result, ok, err := decodeUnmarshaler(raw, targetType); ok {
+		return result, err
+	}
 	switch targetType.Kind() {
 	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
 		return decodeJSON(raw, targetType)
@@ -217,6 +726,9 @@ func (l *Loader) defaultDecode(raw string, targetType reflect.Type) (any, error)
 }
 
 func needsStructuredFormat(t reflect.Type) bool {
+	if implementsUnmarshaler(t) {
+		return false
+	}
 	switch t.Kind() {
 	case reflect.Struct, reflect.Map, reflect.Interface:
 		return true
@@ -3,6 +3,7 @@ package conflata
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -245,13 +246,14 @@ func TestLoaderNestedStructTagDecodesAndOverrides(t *testing.T) {
 	}
 }
 
-func TestLoaderSkipsUntaggedStructFields(t *testing.T) {
+func TestLoaderDescendsIntoUntaggedStructFields(t *testing.T) {
 	type Nested struct {
 		Secret string `conflata:"env:NESTED_SECRET"`
 	}
 	type Config struct {
 		Tagged   Nested `conflata:"env:TAGGED provider:tagged"`
 		Untagged Nested
+		Skipped  Nested `conflata:"-"`
 	}
 
 	env := map[string]string{
@@ -269,8 +271,95 @@ func TestLoaderSkipsUntaggedStructFields(t *testing.T) {
 	if cfg.Tagged.Secret != "override" {
 		t.Fatalf("expected tagged struct to allow nested override, got %+v", cfg.Tagged)
 	}
-	if cfg.Untagged.Secret != "" {
-		t.Fatalf("expected untagged struct to remain zero, got %+v", cfg.Untagged)
+	if cfg.Untagged.Secret != "override" {
+		t.Fatalf("expected untagged struct to be traversed, got %+v", cfg.Untagged)
+	}
+	if cfg.Skipped.Secret != "" {
+		t.Fatalf("expected opted-out struct to remain zero, got %+v", cfg.Skipped)
+	}
+}
+
+func TestLoaderPromotesEmbeddedStructFields(t *testing.T) {
+	type Base struct {
+		Region string `conflata:"env:REGION"`
+		Zone   string `conflata:"env:ZONE"`
+	}
+	type Config struct {
+		Base
+		Name string `conflata:"env:NAME"`
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		if key == "REGION" {
+			return "eu-west-1", true
+		}
+		if key == "NAME" {
+			return "svc", true
+		}
+		return "", false
+	}))
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	if cfg.Region != "eu-west-1" || cfg.Name != "svc" {
+		t.Fatalf("expected embedded fields to load, got %+v", cfg)
+	}
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 {
+		t.Fatalf("expected a single field error, got %v", err)
+	}
+	if path := group.Fields()[0].FieldPath; path != "Zone" {
+		t.Fatalf("expected promoted path Zone, got %s", path)
+	}
+}
+
+func TestLoaderAllocatesUntaggedPointerOnlyWhenResolved(t *testing.T) {
+	type Messaging struct {
+		BrokerURL string `conflata:"env:BROKER_URL default:''"`
+	}
+	type Cache struct {
+		Addr string `conflata:"env:CACHE_ADDR provider:cache-addr"`
+	}
+	type Config struct {
+		Messaging *Messaging
+		Cache     *Cache
+	}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			if key == "BROKER_URL" {
+				return "kafka://env", true
+			}
+			return "", false
+		}),
+		WithProvider("aws", stubProvider{}),
+	)
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	if cfg.Messaging == nil || cfg.Messaging.BrokerURL != "kafka://env" {
+		t.Fatalf("expected messaging pointer to be allocated, got %+v", cfg.Messaging)
+	}
+	if cfg.Cache != nil {
+		t.Fatalf("expected cache pointer to stay nil, got %+v", cfg.Cache)
+	}
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 || group.Fields()[0].FieldPath != "Cache.Addr" {
+		t.Fatalf("expected Cache.Addr failure, got %v", err)
+	}
+}
+
+func TestLoaderSkipsSelfReferentialPointers(t *testing.T) {
+	type Node struct {
+		Name string `conflata:"env:NODE_NAME"`
+		Next *Node
+	}
+	type Config struct {
+		Root Node
+	}
+	loader := New(WithEnvLookup(func(string) (string, bool) { return "root", true }))
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Root.Name != "root" || cfg.Root.Next != nil {
+		t.Fatalf("unexpected node %+v", cfg.Root)
 	}
 }
 
@@ -355,3 +444,477 @@ func TestLoaderDefaultDecodeError(t *testing.T) {
 		t.Fatalf("expected default attempt error, got %v", group.Fields()[0].Attempts[0].Error())
 	}
 }
+
+type blockingProvider struct {
+	release chan struct{}
+	active  chan struct{}
+}
+
+func (b blockingProvider) Fetch(ctx context.Context, key string) (string, error) {
+	b.active <- struct{}{}
+	select {
+	case <-b.release:
+		return key + "-value", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestLoaderConcurrentResolution(t *testing.T) {
+	type Config struct {
+		A string `conflata:"provider:a"`
+		B string `conflata:"provider:b"`
+		C string `conflata:"provider:c"`
+	}
+	provider := blockingProvider{release: make(chan struct{}), active: make(chan struct{}, 3)}
+	loader := New(WithProvider("aws", provider), WithConcurrency(3))
+
+	done := make(chan error, 1)
+	var cfg Config
+	go func() { done <- loader.Load(context.Background(), &cfg) }()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-provider.active:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected three concurrent fetches, saw %d", i)
+		}
+	}
+	close(provider.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.A != "a-value" || cfg.B != "b-value" || cfg.C != "c-value" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoaderConcurrentErrorOrderIsDeterministic(t *testing.T) {
+	type Nested struct {
+		Inner string `conflata:"provider:inner"`
+	}
+	type Config struct {
+		First  string `conflata:"provider:first"`
+		Nested Nested
+		Last   string `conflata:"provider:last"`
+	}
+	loader := New(WithProvider("aws", stubProvider{}), WithConcurrency(4))
+	for i := 0; i < 20; i++ {
+		var cfg Config
+		err := loader.Load(context.Background(), &cfg)
+		group, ok := err.(*ErrorGroup)
+		if !ok {
+			t.Fatalf("expected ErrorGroup, got %T", err)
+		}
+		var paths []string
+		for _, fieldErr := range group.Fields() {
+			paths = append(paths, fieldErr.FieldPath)
+		}
+		if strings.Join(paths, ",") != "First,Nested.Inner,Last" {
+			t.Fatalf("unexpected error order %v", paths)
+		}
+	}
+}
+
+func TestLoaderConcurrentNestedOverride(t *testing.T) {
+	type APISettings struct {
+		BaseURL string `json:"baseUrl"`
+		Token   string `conflata:"provider:api-token"`
+	}
+	type Config struct {
+		API APISettings `conflata:"provider:api/config"`
+	}
+	provider := stubProvider{values: map[string]providerResponse{
+		"api/config": {value: `{"baseUrl":"https://api.provider"}`},
+		"api-token":  {value: "from-provider"},
+	}}
+	loader := New(WithProvider("aws", provider), WithConcurrency(8))
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.API.BaseURL != "https://api.provider" || cfg.API.Token != "from-provider" {
+		t.Fatalf("nested override lost: %+v", cfg.API)
+	}
+}
+
+func TestLoaderConcurrentHonoursCancellation(t *testing.T) {
+	type Config struct {
+		A string `conflata:"provider:a"`
+		B string `conflata:"provider:b"`
+		C string `conflata:"provider:c"`
+	}
+	provider := blockingProvider{release: make(chan struct{}), active: make(chan struct{}, 3)}
+	loader := New(WithProvider("aws", provider), WithConcurrency(2))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	var cfg Config
+	go func() { done <- loader.Load(ctx, &cfg) }()
+	<-provider.active
+	<-provider.active
+	cancel()
+	select {
+	case err := <-done:
+		group, ok := err.(*ErrorGroup)
+		if !ok || len(group.Fields()) != 3 {
+			t.Fatalf("expected all three fields to fail, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Load did not return after cancellation")
+	}
+}
+
+func TestLoaderErrorsMatchSentinels(t *testing.T) {
+	type Config struct {
+		Missing string `conflata:"env:MISSING"`
+		Port    int    `conflata:"env:PORT"`
+		Remote  string `conflata:"provider:remote backend:nowhere"`
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		if key == "PORT" {
+			return "not-a-port", true
+		}
+		return "", false
+	}))
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	for _, sentinel := range []error{ErrNotFound, ErrDecode, ErrProviderNotRegistered} {
+		if !errors.Is(err, sentinel) {
+			t.Fatalf("expected %v to match %v", err, sentinel)
+		}
+	}
+}
+
+func TestLoaderOptionalFieldsStayZero(t *testing.T) {
+	type Messaging struct {
+		BrokerURL string `conflata:"env:BROKER_URL"`
+	}
+	type Config struct {
+		Name      string     `conflata:"env:NAME optional"`
+		Port      *int       `conflata:"env:PORT provider:port optional"`
+		Messaging *Messaging `conflata:"env:MESSAGING optional"`
+		Token     string     `conflata:"env:TOKEN"`
+	}
+	loader := New(
+		WithEnvLookup(func(string) (string, bool) { return "", false }),
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"port": {err: fmt.Errorf("missing: %w", ErrNotFound)},
+		}}),
+	)
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	if cfg.Name != "" || cfg.Port != nil || cfg.Messaging != nil {
+		t.Fatalf("expected optional fields to stay zero, got %+v", cfg)
+	}
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 {
+		t.Fatalf("expected only the required field to fail, got %v", err)
+	}
+	if fieldErr := group.Fields()[0]; fieldErr.FieldPath != "Token" || !fieldErr.Required || !group.HasRequired() {
+		t.Fatalf("expected required Token failure, got %+v", fieldErr)
+	}
+}
+
+func TestLoaderOptionalFieldReportsNonMissingFailures(t *testing.T) {
+	type Config struct {
+		Port  int    `conflata:"env:PORT optional"`
+		Token string `conflata:"provider:token"`
+	}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) { return "not-a-number", key == "PORT" }),
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"token": {value: "secret"},
+		}}),
+	)
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 {
+		t.Fatalf("expected decode failure to be reported, got %v", err)
+	}
+	if group.Fields()[0].Required || group.HasRequired() || len(group.Required()) != 0 {
+		t.Fatalf("expected failure to be marked optional, got %+v", group.Fields())
+	}
+}
+
+func TestLoaderDefaultRequirementPolicy(t *testing.T) {
+	type Config struct {
+		Region string `conflata:"env:REGION"`
+		Token  string `conflata:"env:TOKEN required"`
+	}
+	loader := New(
+		WithEnvLookup(func(string) (string, bool) { return "", false }),
+		WithDefaultRequirement(Optional),
+	)
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 || group.Fields()[0].FieldPath != "Token" {
+		t.Fatalf("expected only the explicitly required field to fail, got %v", err)
+	}
+}
+
+func TestLoaderSourceOrder(t *testing.T) {
+	type Config struct {
+		Region string `conflata:"env:REGION provider:region"`
+		Token  string `conflata:"env:TOKEN provider:token default:fallback order:default,env"`
+		Level  string `conflata:"provider:level default:info"`
+	}
+	loader := New(
+		WithEnvLookup(func(string) (string, bool) { return "from-env", true }),
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"region": {value: "from-provider"},
+			"token":  {value: "unused"},
+		}}),
+		WithSourceOrder(SourceProvider, SourceEnv),
+	)
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Region != "from-provider" || cfg.Token != "fallback" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if entry, _ := report.Field("Token"); entry.Source != SourceDefault {
+		t.Fatalf("expected Token from default, got %s", entry.Source)
+	}
+	// Defaults were left out of WithSourceOrder but still apply last.
+	if entry, _ := report.Field("Level"); cfg.Level != "info" || entry.Source != SourceDefault {
+		t.Fatalf("expected Level from default, got %q from %s", cfg.Level, entry.Source)
+	}
+}
+
+func TestLoaderDisabledSources(t *testing.T) {
+	type Config struct {
+		Debug bool   `conflata:"env:DEBUG optional"`
+		Token string `conflata:"env:TOKEN"`
+	}
+	loader := New(
+		WithEnvLookup(func(string) (string, bool) { return "true", true }),
+		WithDisabledSources(SourceEnv),
+	)
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	if cfg.Debug || cfg.Token != "" {
+		t.Fatalf("expected disabled env to be ignored, got %+v", cfg)
+	}
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 || group.Fields()[0].FieldPath != "Token" {
+		t.Fatalf("expected only the required field to fail, got %v", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLoaderBackendChainFallsThroughOnNotFound(t *testing.T) {
+	type Config struct {
+		Token string `conflata:"provider:token backend:vault,gcp,aws"`
+	}
+	loader := New(
+		WithEnvLookup(func(string) (string, bool) { return "", false }),
+		WithProvider("vault", stubProvider{values: map[string]providerResponse{
+			"token": {err: fmt.Errorf("no such path: %w", ErrNotFound)},
+		}}),
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"token": {value: "from-aws"},
+		}}),
+	)
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Token != "from-aws" {
+		t.Fatalf("expected aws value, got %q", cfg.Token)
+	}
+	entry, _ := report.Field("Token")
+	if entry.Backend != "aws" || len(entry.Attempts) != 2 {
+		t.Fatalf("unexpected report %+v", entry)
+	}
+	if entry.Attempts[0].Identifier != "vault:token" || !errors.Is(entry.Attempts[1].Err, ErrProviderNotRegistered) {
+		t.Fatalf("expected one attempt per backend, got %+v", entry.Attempts)
+	}
+}
+
+func TestLoaderProviderChainStopsOnOtherErrors(t *testing.T) {
+	type Config struct {
+		Token string `conflata:"provider:token backend:secrets default:fallback"`
+	}
+	providers := []Option{
+		WithEnvLookup(func(string) (string, bool) { return "", false }),
+		WithProvider("vault", stubProvider{values: map[string]providerResponse{
+			"token": {err: fmt.Errorf("sealed: %w", ErrTransient)},
+		}}),
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"token": {value: "from-aws"},
+		}}),
+		WithProviderChain("secrets", "vault", "aws"),
+	}
+
+	var cfg Config
+	if err := New(providers...).Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Token != "fallback" {
+		t.Fatalf("expected chain to stop on transient error, got %q", cfg.Token)
+	}
+
+	cfg = Config{}
+	if err := New(append(providers, WithTransientFallthrough())...).Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Token != "from-aws" {
+		t.Fatalf("expected transient fallthrough to reach aws, got %q", cfg.Token)
+	}
+}
+
+func TestLoaderEnvAliases(t *testing.T) {
+	type Config struct {
+		Host string `conflata:"env:APP_HOST,HOST"`
+		Port int    `conflata:"env:APP_PORT,PORT:deprecated"`
+		Mode string `conflata:"env:APP_MODE,MODE:deprecated"`
+	}
+	env := map[string]string{"HOST": "example.com", "PORT": "8080", "APP_MODE": "fast", "MODE": "slow"}
+	var warnings []DeprecationWarning
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}),
+		WithDeprecationHook(func(w DeprecationWarning) { warnings = append(warnings, w) }),
+	)
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg != (Config{Host: "example.com", Port: 8080, Mode: "fast"}) {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	want := DeprecationWarning{FieldPath: "Port", Source: SourceEnv, Identifier: "PORT", Replacement: "APP_PORT"}
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Fatalf("unexpected hook warnings %+v", warnings)
+	}
+	if got := report.Deprecations(); len(got) != 1 || got[0] != want {
+		t.Fatalf("unexpected report warnings %+v", got)
+	}
+	if line := report.Fields[1].String(); !strings.Contains(line, "deprecated, use APP_PORT") {
+		t.Fatalf("expected deprecation in report line, got %q", line)
+	}
+	if entry, _ := report.Field("Host"); entry.Identifier != "HOST" || len(entry.Attempts) != 1 {
+		t.Fatalf("expected alias to resolve after one attempt, got %+v", entry)
+	}
+}
+
+func TestLoaderAutoEnv(t *testing.T) {
+	type Database struct {
+		PoolSize int    `conflata:"provider:db/pool"`
+		Host     string `conflata:"default:localhost"`
+		User     string `conflata:"env:DB_USER default:app"`
+	}
+	type Config struct {
+		Database Database
+	}
+	env := map[string]string{
+		"APP_DATABASE_POOL_SIZE": "8",
+		"APP_DATABASE_HOST":      "db.internal",
+		"APP_DATABASE_USER":      "ignored",
+	}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}),
+		WithAutoEnv("APP"),
+	)
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Database != (Database{PoolSize: 8, Host: "db.internal", User: "app"}) {
+		t.Fatalf("unexpected config %+v", cfg.Database)
+	}
+
+	loader = New(
+		WithEnvLookup(func(key string) (string, bool) { return "9", key == "app-database-poolsize" }),
+		WithAutoEnv("app"),
+		WithEnvNaming(func(segments []string) string { return strings.ToLower(strings.Join(segments, "-")) }),
+		WithProvider("aws", stubProvider{}),
+	)
+	cfg = Config{}
+	err := loader.Load(context.Background(), &cfg)
+	if cfg.Database.PoolSize != 9 {
+		t.Fatalf("expected custom naming strategy to be used, got %+v (%v)", cfg.Database, err)
+	}
+}
+
+func TestLoaderPrefixScopes(t *testing.T) {
+	type DatabaseConfig struct {
+		Username string `conflata:"env:DB_USERNAME provider:db-username"`
+		Password string `conflata:"provider:db-password"`
+	}
+	type Databases struct {
+		Primary DatabaseConfig  `conflata:"envprefix:PRIMARY_ providerprefix:primary/"`
+		Replica *DatabaseConfig `conflata:"envprefix:REPLICA_ providerprefix:replica/"`
+	}
+	type Config struct {
+		Databases Databases `conflata:"envprefix:APP_ providerprefix:prod/"`
+	}
+	env := map[string]string{"APP_PRIMARY_DB_USERNAME": "primary-user"}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}),
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"prod/primary/db-password": {value: "primary-pass"},
+			"prod/replica/db-username": {value: "replica-user"},
+			"prod/replica/db-password": {value: "replica-pass"},
+		}}),
+	)
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Databases.Primary != (DatabaseConfig{Username: "primary-user", Password: "primary-pass"}) {
+		t.Fatalf("unexpected primary %+v", cfg.Databases.Primary)
+	}
+	if cfg.Databases.Replica == nil || *cfg.Databases.Replica != (DatabaseConfig{Username: "replica-user", Password: "replica-pass"}) {
+		t.Fatalf("unexpected replica %+v", cfg.Databases.Replica)
+	}
+}
+
+func TestLoaderProviderKeySelectors(t *testing.T) {
+	type Config struct {
+		Username string `conflata:"provider:prod/db#username"`
+		Port     int    `conflata:"provider:prod/db#/conn/port"`
+		Password string `conflata:"provider:prod/db#password"`
+	}
+	var fetches int
+	loader := New(
+		WithProvider("aws", stubProvider{values: map[string]providerResponse{
+			"prod/db": {value: `{"username":"app","conn":{"port":5432}}`},
+		}}),
+		WithFetchHook(func(FetchEvent) { fetches++ }),
+	)
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if cfg.Username != "app" || cfg.Port != 5432 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if entry, _ := report.Field("Port"); entry.Key != "prod/db" || entry.Selector != "/conn/port" || !entry.Shared {
+		t.Fatalf("unexpected report %+v", entry)
+	}
+	var group *ErrorGroup
+	if !errors.As(err, &group) || len(group.Fields()) != 1 {
+		t.Fatalf("expected missing selector to fail, got %v", err)
+	}
+	attempt := group.Fields()[0].Attempts[0]
+	if attempt.Identifier != "aws:prod/db#password" || !errors.Is(attempt.Err, ErrNotFound) || !strings.Contains(attempt.Err.Error(), `no member "password"`) {
+		t.Fatalf("unexpected attempt %+v", attempt)
+	}
+	if fetches != 3 {
+		t.Fatalf("expected one fetch event per field, got %d", fetches)
+	}
+}
@@ -0,0 +1,128 @@
+package conflata
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives an environment variable name from the segments of a
+// field path, such as ["Database", "PoolSize"] for Database.PoolSize.
+type NamingStrategy func(segments []string) string
+
+// SnakeOption configures UpperSnake.
+type SnakeOption func(*snakeNamer)
+
+// SnakeSeparator sets the string placed between words. Defaults to "_".
+func SnakeSeparator(sep string) SnakeOption {
+	return func(n *snakeNamer) {
+		n.sep = sep
+	}
+}
+
+// SnakeAcronyms lists words that are kept whole even though their case would
+// otherwise split them, such as "OAuth" or "IPv6". Runs of capitals like
+// "HTTP" or "ID" are kept whole without being listed.
+func SnakeAcronyms(acronyms ...string) SnakeOption {
+	return func(n *snakeNamer) {
+		n.acronyms = append(n.acronyms, acronyms...)
+	}
+}
+
+// UpperSnake returns the default naming strategy, which splits every path
+// segment into words at case changes and joins them upper-cased, so
+// Database.PoolSize becomes DATABASE_POOL_SIZE and HTTPTimeout becomes
+// HTTP_TIMEOUT.
+func UpperSnake(opts ...SnakeOption) NamingStrategy {
+	n := &snakeNamer{sep: "_"}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n.name
+}
+
+type snakeNamer struct {
+	sep      string
+	acronyms []string
+}
+
+func (n *snakeNamer) name(segments []string) string {
+	var words []string
+	for _, segment := range segments {
+		words = append(words, n.split(segment)...)
+	}
+	return strings.ToUpper(strings.Join(words, n.sep))
+}
+
+// split breaks s into words before a capital that follows a lower-case letter
+// or digit, and before the last capital of an upper-case run followed by lower
+// case. Underscores also separate words; digits stay with the word before them.
+func (n *snakeNamer) split(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		if acronym := n.acronymAt(runes[i:]); acronym > 0 {
+			flush()
+			words = append(words, string(runes[i:i+acronym]))
+			i += acronym
+			continue
+		}
+		r := runes[i]
+		if r == '_' {
+			flush()
+			i++
+			continue
+		}
+		if len(word) > 0 {
+			prev := word[len(word)-1]
+			switch {
+			case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+				flush()
+			case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				flush()
+			}
+		}
+		word = append(word, r)
+		i++
+	}
+	flush()
+	return words
+}
+
+// acronymAt returns the length of a configured acronym at the start of runes,
+// provided it is not immediately followed by a lower-case letter.
+func (n *snakeNamer) acronymAt(runes []rune) int {
+	for _, acronym := range n.acronyms {
+		candidate := []rune(acronym)
+		if len(candidate) == 0 || len(candidate) > len(runes) || string(runes[:len(candidate)]) != acronym {
+			continue
+		}
+		if len(candidate) < len(runes) && unicode.IsLower(runes[len(candidate)]) {
+			continue
+		}
+		return len(candidate)
+	}
+	return 0
+}
+
+// autoEnvKey derives the env key for fieldPath under the WithAutoEnv settings.
+// The prefix is passed to the naming strategy as the first segment.
+func (l *Loader) autoEnvKey(fieldPath string) string {
+	segments := strings.Split(fieldPath, ".")
+	if l.autoEnvPrefix != "" {
+		segments = append([]string{l.autoEnvPrefix}, segments...)
+	}
+	naming := l.envNaming
+	if naming == nil {
+		naming = UpperSnake()
+	}
+	return naming(segments)
+}
@@ -0,0 +1,26 @@
+package conflata
+
+import "testing"
+
+func TestUpperSnake(t *testing.T) {
+	cases := []struct {
+		name     string
+		strategy NamingStrategy
+		segments []string
+		want     string
+	}{
+		{"nested", UpperSnake(), []string{"APP", "Database", "PoolSize"}, "APP_DATABASE_POOL_SIZE"},
+		{"acronym run", UpperSnake(), []string{"HTTPTimeout"}, "HTTP_TIMEOUT"},
+		{"trailing acronym", UpperSnake(), []string{"UserID"}, "USER_ID"},
+		{"digits", UpperSnake(), []string{"Port2Name"}, "PORT2_NAME"},
+		{"underscores", UpperSnake(), []string{"my_app", "Token"}, "MY_APP_TOKEN"},
+		{"separator", UpperSnake(SnakeSeparator("__")), []string{"Database", "PoolSize"}, "DATABASE__POOL__SIZE"},
+		{"without acronyms", UpperSnake(), []string{"OAuthToken"}, "O_AUTH_TOKEN"},
+		{"acronyms", UpperSnake(SnakeAcronyms("OAuth", "IPv6")), []string{"OAuthToken", "BindIPv6"}, "OAUTH_TOKEN_BIND_IPV6"},
+	}
+	for _, tc := range cases {
+		if got := tc.strategy(tc.segments); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -27,6 +27,57 @@ func WithDefaultProvider(name string) Option {
 	}
 }
 
+// WithProviderChain registers name as an ordered list of backends. A tag with
+// `backend:name`, or a loader whose default provider is name, tries each backend
+// in turn and moves on only when the value is not found or the backend is not
+// registered (see WithTransientFallthrough). Every backend tried is reported as
+// its own AttemptError. Tags may also list backends directly, as in
+// `backend:vault,aws`.
+func WithProviderChain(name string, backends ...string) Option {
+	return func(l *Loader) {
+		if name == "" || len(backends) == 0 {
+			return
+		}
+		if l.chains == nil {
+			l.chains = make(map[string][]string)
+		}
+		members := make([]string, 0, len(backends))
+		for _, backend := range backends {
+			members = append(members, strings.ToLower(backend))
+		}
+		l.chains[strings.ToLower(name)] = members
+	}
+}
+
+// WithTransientFallthrough lets fallback chains also move on to the next
+// backend when one fails with an error classified as ErrTransient.
+func WithTransientFallthrough() Option {
+	return func(l *Loader) {
+		l.transientFallthrough = true
+	}
+}
+
+// WithAutoEnv derives an env key from the field path for every tagged field
+// that has a provider or default but no `env:` key, so Database.PoolSize with
+// prefix "APP" reads APP_DATABASE_POOL_SIZE. Names are built by UpperSnake
+// unless WithEnvNaming supplies another strategy. An empty prefix derives
+// unprefixed names.
+func WithAutoEnv(prefix string) Option {
+	return func(l *Loader) {
+		l.autoEnv = true
+		l.autoEnvPrefix = prefix
+	}
+}
+
+// WithEnvNaming sets the naming strategy used by WithAutoEnv.
+func WithEnvNaming(strategy NamingStrategy) Option {
+	return func(l *Loader) {
+		if strategy != nil {
+			l.envNaming = strategy
+		}
+	}
+}
+
 // WithEnvLookup overrides the environment variable lookup strategy.
 func WithEnvLookup(fn EnvLookupFunc) Option {
 	return func(l *Loader) {
@@ -50,6 +101,21 @@ func WithDecoder(name string, fn DecodeFunc) Option {
 	}
 }
 
+// WithTransform registers a transform keyed by name so struct tags can run it
+// via `transform:name`. Registering a built-in name (base64, base64url, hex,
+// gunzip, trimspace, unquote) replaces it.
+func WithTransform(name string, fn TransformFunc) Option {
+	return func(l *Loader) {
+		if name == "" || fn == nil {
+			return
+		}
+		if l.transforms == nil {
+			l.transforms = make(map[string]TransformFunc)
+		}
+		l.transforms[strings.ToLower(name)] = fn
+	}
+}
+
 // WithDefaultFormat overrides the default decoder used for structured types
 // when no per-field format is provided.
 func WithDefaultFormat(name string) Option {
@@ -73,3 +139,110 @@ func WithProviderSuffix(fn func() string) Option {
 		l.suffixFunc = fn
 	}
 }
+
+// WithConcurrency resolves up to n fields at the same time. Values of n below
+// two keep the default sequential behaviour. Nested fields still resolve after
+// their parent so structured payloads are assigned before child overrides, and
+// ErrorGroup entries keep struct declaration order.
+func WithConcurrency(n int) Option {
+	return func(l *Loader) {
+		l.concurrency = n
+	}
+}
+
+// WithSharedFetches coalesces identical provider lookups across concurrent Load
+// calls on the same Loader, in the style of singleflight: while a fetch for a
+// backend/key pair is in flight, other Loads wait for its result instead of
+// issuing their own request. A fetch that fails because the context of the
+// Load issuing it ended is not shared; waiting Loads fetch again under their
+// own context. Lookups within a single Load are always shared.
+func WithSharedFetches() Option {
+	return func(l *Loader) {
+		l.inflight = newFetchGroup(false)
+	}
+}
+
+// WithFetchHook registers a callback invoked after every provider lookup made
+// for a field, including lookups served from a shared fetch. The hook may be
+// called from multiple goroutines when WithConcurrency is in effect.
+func WithFetchHook(fn func(FetchEvent)) Option {
+	return func(l *Loader) {
+		l.fetchHook = fn
+	}
+}
+
+// WithDeprecationHook registers a callback invoked whenever a field is resolved
+// from a deprecated environment variable alias. The hook may be called from
+// multiple goroutines when WithConcurrency is in effect.
+func WithDeprecationHook(fn func(DeprecationWarning)) Option {
+	return func(l *Loader) {
+		l.deprecationHook = fn
+	}
+}
+
+// WithDefaultRequirement sets whether fields are Required or Optional when their
+// tag does not say so explicitly with the `optional` or `required` keys.
+func WithDefaultRequirement(r Requirement) Option {
+	return func(l *Loader) {
+		l.defaultRequirement = r
+	}
+}
+
+// WithCommitPolicy makes Load transactional. With CommitOnSuccess or
+// CommitUnlessRequired, fields are resolved into a detached copy of the target
+// and the target is overwritten in a single assignment only when the policy
+// allows it; otherwise it is left untouched. Concurrent readers still need
+// their own synchronisation around that assignment.
+func WithCommitPolicy(p CommitPolicy) Option {
+	return func(l *Loader) {
+		l.commitPolicy = p
+	}
+}
+
+// WithVerboseDecodeErrors keeps decoder error messages as the decoder produced
+// them. By default the loader scrubs the raw value from decode errors so
+// secrets do not end up in ErrorGroup messages or logs.
+func WithVerboseDecodeErrors() Option {
+	return func(l *Loader) {
+		l.verboseDecodeErrors = true
+	}
+}
+
+// WithSourceOrder sets the order in which sources are consulted for fields
+// without an `order:` tag key. Valid sources are SourceEnv, SourceProvider, and
+// SourceDefault; sources left out are consulted after the listed ones in their
+// default relative order. Use WithDisabledSources to turn a source off. The
+// default order is env, provider, default.
+func WithSourceOrder(sources ...ValueSource) Option {
+	return func(l *Loader) {
+		var order []ValueSource
+		seen := make(map[ValueSource]bool)
+		for _, class := range sources {
+			if orderableSource(class) && !seen[class] {
+				seen[class] = true
+				order = append(order, class)
+			}
+		}
+		for _, class := range defaultSourceOrder {
+			if !seen[class] {
+				order = append(order, class)
+			}
+		}
+		l.sourceOrder = order
+	}
+}
+
+// WithDisabledSources turns off whole source classes, for example env
+// overrides in production, including for fields whose `order:` tag lists them.
+// Fields left without any enabled source fail as not found, so optional fields
+// keep their zero value.
+func WithDisabledSources(sources ...ValueSource) Option {
+	return func(l *Loader) {
+		if l.disabledSources == nil {
+			l.disabledSources = make(map[ValueSource]bool)
+		}
+		for _, class := range sources {
+			l.disabledSources[class] = true
+		}
+	}
+}
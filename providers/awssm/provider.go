@@ -4,9 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/djbozjr/conflata"
 )
 
 // SecretsManagerClient captures the subset of the AWS Secrets Manager client
@@ -15,12 +22,21 @@ type SecretsManagerClient interface {
 	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
 }
 
+// SecretDescriber is implemented by Secrets Manager clients that can describe a
+// secret without retrieving its value. *secretsmanager.Client satisfies this
+// interface; when the client passed to New implements it, Watch uses
+// DescribeSecret instead of GetSecretValue to detect rotations.
+type SecretDescriber interface {
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+}
+
 // Provider loads values from AWS Secrets Manager.
 type Provider struct {
-	client       SecretsManagerClient
-	versionStage *string
-	versionID    *string
-	callOpts     []func(*secretsmanager.Options)
+	client        SecretsManagerClient
+	versionStage  *string
+	versionID     *string
+	callOpts      []func(*secretsmanager.Options)
+	watchInterval time.Duration
 }
 
 // Option configures the AWS provider.
@@ -51,13 +67,24 @@ func WithClientOptions(opts ...func(*secretsmanager.Options)) Option {
 	}
 }
 
+// WithWatchInterval sets how often Watch checks secrets for a new version.
+// Defaults to one minute.
+func WithWatchInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.watchInterval = d
+		}
+	}
+}
+
 // New constructs a Secrets Manager provider.
 func New(client SecretsManagerClient, opts ...Option) (*Provider, error) {
 	if client == nil {
 		return nil, errors.New("awssm: client is required")
 	}
 	p := &Provider{
-		client: client,
+		client:        client,
+		watchInterval: time.Minute,
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -67,8 +94,16 @@ func New(client SecretsManagerClient, opts ...Option) (*Provider, error) {
 
 // Fetch retrieves the secret with the provided key.
 func (p *Provider) Fetch(ctx context.Context, key string) (string, error) {
+	value, _, err := p.FetchWithMetadata(ctx, key)
+	return value, err
+}
+
+// FetchWithMetadata retrieves the secret along with its version ID, creation
+// date, and version stages.
+func (p *Provider) FetchWithMetadata(ctx context.Context, key string) (string, conflata.ValueMetadata, error) {
+	var meta conflata.ValueMetadata
 	if key == "" {
-		return "", errors.New("awssm: secret id cannot be empty")
+		return "", meta, errors.New("awssm: secret id cannot be empty")
 	}
 	input := &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(key),
@@ -81,13 +116,100 @@ func (p *Provider) Fetch(ctx context.Context, key string) (string, error) {
 	}
 	out, err := p.client.GetSecretValue(ctx, input, p.callOpts...)
 	if err != nil {
-		return "", fmt.Errorf("awssm: %w", err)
+		return "", meta, p.wrap(err)
+	}
+	meta.Version = aws.ToString(out.VersionId)
+	meta.CreatedAt = aws.ToTime(out.CreatedDate)
+	if len(out.VersionStages) > 0 {
+		meta.Attributes = map[string]string{"stages": strings.Join(out.VersionStages, ",")}
 	}
 	if out.SecretString != nil {
-		return aws.ToString(out.SecretString), nil
+		return aws.ToString(out.SecretString), meta, nil
 	}
 	if len(out.SecretBinary) > 0 {
-		return string(out.SecretBinary), nil
+		return string(out.SecretBinary), meta, nil
+	}
+	return "", meta, fmt.Errorf("awssm: secret contained no payload: %w", conflata.ErrEmptyValue)
+}
+
+// Watch reports keys whose secret moved to a new version in the requested
+// stage (AWSCURRENT unless WithVersionStage was used), for example after a
+// rotation. It implements conflata.Watcher by polling every watch interval.
+func (p *Provider) Watch(ctx context.Context, keys []string) (<-chan string, error) {
+	return conflata.PollVersions(ctx, keys, p.watchInterval, p.currentVersion), nil
+}
+
+// currentVersion returns the version ID currently holding the requested stage.
+func (p *Provider) currentVersion(ctx context.Context, key string) (string, error) {
+	describer, ok := p.client.(SecretDescriber)
+	if !ok || p.versionID != nil {
+		_, meta, err := p.FetchWithMetadata(ctx, key)
+		return meta.Version, err
+	}
+	out, err := describer.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(key)}, p.callOpts...)
+	if err != nil {
+		return "", p.wrap(err)
+	}
+	stage := "AWSCURRENT"
+	if p.versionStage != nil {
+		stage = *p.versionStage
+	}
+	for id, stages := range out.VersionIdsToStages {
+		for _, s := range stages {
+			if s == stage {
+				return id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("awssm: no version in stage %s: %w", stage, conflata.ErrNotFound)
+}
+
+// wrap prefixes err and attaches the matching conflata sentinel, if any.
+func (p *Provider) wrap(err error) error {
+	if kind := p.classify(err); kind != nil {
+		return fmt.Errorf("awssm: %w: %w", kind, err)
+	}
+	return fmt.Errorf("awssm: %w", err)
+}
+
+// classify maps Secrets Manager errors onto conflata sentinel errors. It
+// returns nil when the error does not fit a known category.
+func (p *Provider) classify(err error) error {
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return conflata.ErrNotFound
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException" {
+		return conflata.ErrPermissionDenied
+	}
+	if p.IsTransient(err) {
+		return conflata.ErrTransient
+	}
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) && statusErr.HTTPStatusCode() == http.StatusForbidden {
+		return conflata.ErrPermissionDenied
+	}
+	return nil
+}
+
+// IsTransient reports whether err is a throttling response or a server-side
+// failure that is worth retrying.
+func (p *Provider) IsTransient(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "Throttling", "TooManyRequestsException", "RequestLimitExceeded", "InternalServiceError":
+			return true
+		}
+		if apiErr.ErrorFault() == smithy.FaultServer {
+			return true
+		}
+	}
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		code := statusErr.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
 	}
-	return "", errors.New("awssm: secret contained no payload")
+	return false
 }
@@ -3,10 +3,18 @@ package awssm
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/djbozjr/conflata"
 )
 
 type stubClient struct {
@@ -95,3 +103,103 @@ func TestProviderPropagatesError(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestProviderIsTransient(t *testing.T) {
+	provider, err := New(&stubClient{})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&smithy.GenericAPIError{Code: "ThrottlingException"}, true},
+		{&smithy.GenericAPIError{Code: "InternalServiceError", Fault: smithy.FaultServer}, true},
+		{&smithy.GenericAPIError{Code: "ResourceNotFoundException", Fault: smithy.FaultClient}, false},
+		{&smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 503}}}, true},
+		{errors.New("boom"), false},
+	}
+	for _, tc := range cases {
+		if got := provider.IsTransient(fmt.Errorf("awssm: %w", tc.err)); got != tc.want {
+			t.Fatalf("IsTransient(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestProviderMapsErrorsToSentinels(t *testing.T) {
+	cases := []struct {
+		err  error
+		want error
+	}{
+		{&types.ResourceNotFoundException{Message: aws.String("missing")}, conflata.ErrNotFound},
+		{&smithy.GenericAPIError{Code: "AccessDeniedException"}, conflata.ErrPermissionDenied},
+		{&smithy.GenericAPIError{Code: "ThrottlingException"}, conflata.ErrTransient},
+	}
+	for _, tc := range cases {
+		provider, err := New(&stubClient{err: tc.err})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if _, err := provider.Fetch(context.Background(), "secret"); !errors.Is(err, tc.want) {
+			t.Fatalf("expected %v to match %v", err, tc.want)
+		}
+	}
+	provider, _ := New(&stubClient{out: &secretsmanager.GetSecretValueOutput{}})
+	if _, err := provider.Fetch(context.Background(), "secret"); !errors.Is(err, conflata.ErrEmptyValue) {
+		t.Fatalf("expected empty payload to match ErrEmptyValue, got %v", err)
+	}
+}
+
+func TestProviderFetchWithMetadata(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	stub := &stubClient{
+		out: &secretsmanager.GetSecretValueOutput{
+			SecretString:  aws.String("value"),
+			VersionId:     aws.String("v-123"),
+			VersionStages: []string{"AWSCURRENT"},
+			CreatedDate:   aws.Time(created),
+		},
+	}
+	provider, err := New(stub)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	_, meta, err := provider.FetchWithMetadata(context.Background(), "secret")
+	if err != nil {
+		t.Fatalf("FetchWithMetadata error: %v", err)
+	}
+	if meta.Version != "v-123" || !meta.CreatedAt.Equal(created) || meta.Attributes["stages"] != "AWSCURRENT" {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+}
+
+type describingClient struct {
+	stubClient
+	stages map[string][]string
+}
+
+func (d *describingClient) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	return &secretsmanager.DescribeSecretOutput{VersionIdsToStages: d.stages}, nil
+}
+
+var _ SecretDescriber = (*secretsmanager.Client)(nil)
+
+func TestProviderCurrentVersionUsesDescribeSecret(t *testing.T) {
+	client := &describingClient{stages: map[string][]string{
+		"v1": {"AWSPREVIOUS"},
+		"v2": {"AWSCURRENT"},
+		"v3": {"AWSPENDING"},
+	}}
+	provider, _ := New(client)
+	version, err := provider.currentVersion(context.Background(), "secret")
+	if err != nil || version != "v2" {
+		t.Fatalf("expected v2, got %q (%v)", version, err)
+	}
+	provider, _ = New(client, WithVersionStage("AWSPENDING"))
+	if version, _ := provider.currentVersion(context.Background(), "secret"); version != "v3" {
+		t.Fatalf("expected v3 for the pending stage, got %q", version)
+	}
+	if client.input != nil {
+		t.Fatalf("expected GetSecretValue not to be called")
+	}
+}
@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/djbozjr/conflata"
+)
+
+// Provider wraps another conflata.Provider and caches fetched values per key.
+// It is safe for concurrent use.
+type Provider struct {
+	next        conflata.Provider
+	ttl         func(key string) time.Duration
+	negativeTTL time.Duration
+	staleWindow time.Duration
+	maxEntries  int
+	isNotFound  func(error) bool
+	isTransient func(error) bool
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type entry struct {
+	key       string
+	value     string
+	meta      conflata.ValueMetadata
+	err       error
+	expiresAt time.Time
+}
+
+// Option configures the caching provider.
+type Option func(*Provider)
+
+// WithTTL sets how long successfully fetched values are served from cache.
+// Defaults to five minutes.
+func WithTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		if ttl > 0 {
+			p.ttl = func(string) time.Duration { return ttl }
+		}
+	}
+}
+
+// WithKeyTTL chooses the TTL per key, for example to refresh rotating
+// credentials more often than static settings. A non-positive result disables
+// caching for that key.
+func WithKeyTTL(fn func(key string) time.Duration) Option {
+	return func(p *Provider) {
+		if fn != nil {
+			p.ttl = fn
+		}
+	}
+}
+
+// WithNegativeTTL caches not-found results for the given duration so missing
+// keys do not hit the backend on every load. Zero disables negative caching.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.negativeTTL = ttl
+	}
+}
+
+// WithMaxEntries bounds the cache size. The least recently used entry is
+// evicted once the limit is reached. Zero means unbounded.
+func WithMaxEntries(n int) Option {
+	return func(p *Provider) {
+		p.maxEntries = n
+	}
+}
+
+// WithStaleWhileRevalidate keeps expired values around for window after their
+// TTL. Expired keys are always revalidated against the wrapped provider, but
+// when that fails with a transient error the stale value is served instead.
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(p *Provider) {
+		p.staleWindow = window
+	}
+}
+
+// WithNotFound overrides how not-found errors are recognised for negative
+// caching. By default errors matching conflata.ErrNotFound are cached.
+func WithNotFound(fn func(error) bool) Option {
+	return func(p *Provider) {
+		if fn != nil {
+			p.isNotFound = fn
+		}
+	}
+}
+
+// WithTransient overrides how transient errors are recognised for
+// stale-while-revalidate. By default the wrapped provider's
+// conflata.TransientClassifier implementation is used when available, falling
+// back to conflata.IsTransient.
+func WithTransient(fn func(error) bool) Option {
+	return func(p *Provider) {
+		if fn != nil {
+			p.isTransient = fn
+		}
+	}
+}
+
+// New wraps next with a cache.
+func New(next conflata.Provider, opts ...Option) (*Provider, error) {
+	if next == nil {
+		return nil, errors.New("cache: provider is required")
+	}
+	p := &Provider{
+		next:        next,
+		ttl:         func(string) time.Duration { return 5 * time.Minute },
+		isNotFound:  isNotFound,
+		isTransient: conflata.IsTransient,
+		now:         time.Now,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+	}
+	if classifier, ok := next.(conflata.TransientClassifier); ok {
+		p.isTransient = classifier.IsTransient
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Fetch returns the cached value for key when it is still fresh and otherwise
+// consults the wrapped provider.
+func (p *Provider) Fetch(ctx context.Context, key string) (string, error) {
+	value, _, err := p.FetchWithMetadata(ctx, key)
+	return value, err
+}
+
+// FetchWithMetadata is like Fetch but also returns the metadata reported by
+// the wrapped provider when it implements conflata.MetadataProvider. Cached
+// values keep the metadata from the fetch that populated them.
+func (p *Provider) FetchWithMetadata(ctx context.Context, key string) (string, conflata.ValueMetadata, error) {
+	now := p.now()
+	stale, ok := p.lookup(key)
+	if ok && now.Before(stale.expiresAt) {
+		return stale.value, stale.meta, stale.err
+	}
+
+	value, meta, err := p.fetchNext(ctx, key)
+	switch {
+	case err == nil:
+		if ttl := p.ttl(key); ttl > 0 {
+			p.store(entry{key: key, value: value, meta: meta, expiresAt: now.Add(ttl)})
+		}
+	case p.negativeTTL > 0 && p.isNotFound(err):
+		p.store(entry{key: key, err: err, expiresAt: now.Add(p.negativeTTL)})
+	case ok && stale.err == nil && p.isTransient(err) && now.Before(stale.expiresAt.Add(p.staleWindow)):
+		return stale.value, stale.meta, nil
+	}
+	return value, meta, err
+}
+
+func (p *Provider) fetchNext(ctx context.Context, key string) (string, conflata.ValueMetadata, error) {
+	if mp, ok := p.next.(conflata.MetadataProvider); ok {
+		return mp.FetchWithMetadata(ctx, key)
+	}
+	value, err := p.next.Fetch(ctx, key)
+	return value, conflata.ValueMetadata{}, err
+}
+
+// IsTransient reports whether err is treated as transient, so wrappers stacked
+// on top of the cache classify errors the same way.
+func (p *Provider) IsTransient(err error) bool {
+	return p.isTransient(err)
+}
+
+// Watch forwards to the wrapped provider when it implements conflata.Watcher,
+// invalidating each changed key before reporting it so the following fetch
+// sees the new value. It returns errors.ErrUnsupported otherwise.
+func (p *Provider) Watch(ctx context.Context, keys []string) (<-chan string, error) {
+	watcher, ok := p.next.(conflata.Watcher)
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+	upstream, err := watcher.Watch(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan string)
+	go func() {
+		defer close(changes)
+		for key := range upstream {
+			p.Invalidate(key)
+			select {
+			case changes <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// Invalidate drops any cached result for key.
+func (p *Provider) Invalidate(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if el, ok := p.entries[key]; ok {
+		p.lru.Remove(el)
+		delete(p.entries, key)
+	}
+}
+
+// InvalidateAll empties the cache.
+func (p *Provider) InvalidateAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = make(map[string]*list.Element)
+	p.lru.Init()
+}
+
+// Len reports the number of cached entries, including expired ones that have
+// not been evicted yet.
+func (p *Provider) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lru.Len()
+}
+
+func (p *Provider) lookup(key string) (entry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	el, ok := p.entries[key]
+	if !ok {
+		return entry{}, false
+	}
+	p.lru.MoveToFront(el)
+	return *el.Value.(*entry), true
+}
+
+func (p *Provider) store(e entry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if el, ok := p.entries[e.key]; ok {
+		*el.Value.(*entry) = e
+		p.lru.MoveToFront(el)
+		return
+	}
+	p.entries[e.key] = p.lru.PushFront(&e)
+	for p.maxEntries > 0 && p.lru.Len() > p.maxEntries {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.entries, oldest.Value.(*entry).key)
+	}
+}
+
+func isNotFound(err error) bool {
+	if errors.Is(err, conflata.ErrNotFound) {
+		return true
+	}
+	var nf interface{ NotFound() bool }
+	return errors.As(err, &nf) && nf.NotFound()
+}
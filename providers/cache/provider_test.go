@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string  { return "not found" }
+func (notFoundError) NotFound() bool { return true }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type stubProvider struct {
+	calls  map[string]int
+	values map[string]string
+	err    error
+}
+
+func (s *stubProvider) Fetch(ctx context.Context, key string) (string, error) {
+	if s.calls == nil {
+		s.calls = make(map[string]int)
+	}
+	s.calls[key]++
+	if s.err != nil {
+		return "", s.err
+	}
+	if value, ok := s.values[key]; ok {
+		return value, nil
+	}
+	return "", notFoundError{}
+}
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time          { return c.now }
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestProvider(t *testing.T, next *stubProvider, opts ...Option) (*Provider, *clock) {
+	t.Helper()
+	p, err := New(next, opts...)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	c := &clock{now: time.Unix(1700000000, 0)}
+	p.now = c.Now
+	return p, c
+}
+
+func TestProviderCachesUntilTTL(t *testing.T) {
+	stub := &stubProvider{values: map[string]string{"db": "v1"}}
+	p, c := newTestProvider(t, stub, WithTTL(time.Minute))
+	for i := 0; i < 3; i++ {
+		got, err := p.Fetch(context.Background(), "db")
+		if err != nil || got != "v1" {
+			t.Fatalf("Fetch = %q, %v", got, err)
+		}
+	}
+	if stub.calls["db"] != 1 {
+		t.Fatalf("expected one backend call, got %d", stub.calls["db"])
+	}
+	stub.values["db"] = "v2"
+	c.Advance(time.Minute)
+	if got, _ := p.Fetch(context.Background(), "db"); got != "v2" {
+		t.Fatalf("expected refreshed value, got %q", got)
+	}
+}
+
+func TestProviderKeyTTL(t *testing.T) {
+	stub := &stubProvider{values: map[string]string{"static": "s", "volatile": "v"}}
+	p, _ := newTestProvider(t, stub, WithKeyTTL(func(key string) time.Duration {
+		if key == "volatile" {
+			return 0
+		}
+		return time.Hour
+	}))
+	for i := 0; i < 2; i++ {
+		_, _ = p.Fetch(context.Background(), "static")
+		_, _ = p.Fetch(context.Background(), "volatile")
+	}
+	if stub.calls["static"] != 1 || stub.calls["volatile"] != 2 {
+		t.Fatalf("unexpected call counts %v", stub.calls)
+	}
+}
+
+func TestProviderNegativeCaching(t *testing.T) {
+	stub := &stubProvider{}
+	p, c := newTestProvider(t, stub, WithNegativeTTL(10*time.Second))
+	for i := 0; i < 2; i++ {
+		if _, err := p.Fetch(context.Background(), "missing"); err == nil {
+			t.Fatal("expected not-found error")
+		}
+	}
+	if stub.calls["missing"] != 1 {
+		t.Fatalf("expected negative cache hit, got %d calls", stub.calls["missing"])
+	}
+	c.Advance(10 * time.Second)
+	_, _ = p.Fetch(context.Background(), "missing")
+	if stub.calls["missing"] != 2 {
+		t.Fatalf("expected negative entry to expire, got %d calls", stub.calls["missing"])
+	}
+}
+
+func TestProviderDoesNotCacheOtherErrors(t *testing.T) {
+	stub := &stubProvider{err: errors.New("denied")}
+	p, _ := newTestProvider(t, stub, WithNegativeTTL(time.Minute))
+	_, _ = p.Fetch(context.Background(), "db")
+	_, _ = p.Fetch(context.Background(), "db")
+	if stub.calls["db"] != 2 {
+		t.Fatalf("expected errors to bypass cache, got %d calls", stub.calls["db"])
+	}
+}
+
+func TestProviderStaleWhileRevalidate(t *testing.T) {
+	stub := &stubProvider{values: map[string]string{"db": "v1"}}
+	p, c := newTestProvider(t, stub, WithTTL(time.Minute), WithStaleWhileRevalidate(time.Minute))
+	if _, err := p.Fetch(context.Background(), "db"); err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	c.Advance(90 * time.Second)
+	stub.err = timeoutError{}
+	got, err := p.Fetch(context.Background(), "db")
+	if err != nil || got != "v1" {
+		t.Fatalf("expected stale value on transient error, got %q, %v", got, err)
+	}
+	stub.err = errors.New("denied")
+	if _, err := p.Fetch(context.Background(), "db"); err == nil {
+		t.Fatal("expected non-transient error to surface")
+	}
+	stub.err = timeoutError{}
+	c.Advance(time.Minute)
+	if _, err := p.Fetch(context.Background(), "db"); err == nil {
+		t.Fatal("expected error once the stale window elapsed")
+	}
+}
+
+func TestProviderEvictsLeastRecentlyUsed(t *testing.T) {
+	stub := &stubProvider{values: map[string]string{"a": "1", "b": "2", "c": "3"}}
+	p, _ := newTestProvider(t, stub, WithMaxEntries(2))
+	_, _ = p.Fetch(context.Background(), "a")
+	_, _ = p.Fetch(context.Background(), "b")
+	_, _ = p.Fetch(context.Background(), "a")
+	_, _ = p.Fetch(context.Background(), "c")
+	if p.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", p.Len())
+	}
+	_, _ = p.Fetch(context.Background(), "a")
+	_, _ = p.Fetch(context.Background(), "b")
+	if stub.calls["a"] != 1 || stub.calls["b"] != 2 {
+		t.Fatalf("expected b to be evicted, got %v", stub.calls)
+	}
+}
+
+func TestProviderInvalidate(t *testing.T) {
+	stub := &stubProvider{values: map[string]string{"a": "1", "b": "2"}}
+	p, _ := newTestProvider(t, stub)
+	_, _ = p.Fetch(context.Background(), "a")
+	_, _ = p.Fetch(context.Background(), "b")
+	p.Invalidate("a")
+	_, _ = p.Fetch(context.Background(), "a")
+	_, _ = p.Fetch(context.Background(), "b")
+	if stub.calls["a"] != 2 || stub.calls["b"] != 1 {
+		t.Fatalf("unexpected calls after Invalidate: %v", stub.calls)
+	}
+	p.InvalidateAll()
+	if p.Len() != 0 {
+		t.Fatalf("expected empty cache, got %d entries", p.Len())
+	}
+}
+
+func TestNewRequiresProvider(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("expected error when provider is nil")
+	}
+}
+
+type watchingProvider struct {
+	stubProvider
+	changes chan string
+}
+
+func (w *watchingProvider) Watch(context.Context, []string) (<-chan string, error) {
+	return w.changes, nil
+}
+
+func TestProviderWatchInvalidatesChangedKeys(t *testing.T) {
+	next := &watchingProvider{
+		stubProvider: stubProvider{values: map[string]string{"token": "one"}},
+		changes:      make(chan string),
+	}
+	p, _ := New(next)
+	if _, err := p.Fetch(context.Background(), "token"); err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := p.Watch(ctx, []string{"token"})
+	if err != nil {
+		t.Fatalf("Watch error: %v", err)
+	}
+	next.values["token"] = "two"
+	next.changes <- "token"
+	if key := <-changes; key != "token" {
+		t.Fatalf("expected token change, got %q", key)
+	}
+	if value, _ := p.Fetch(context.Background(), "token"); value != "two" {
+		t.Fatalf("expected invalidated key to be refetched, got %q", value)
+	}
+
+	plain, _ := New(&stubProvider{})
+	if _, err := plain.Watch(ctx, nil); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/djbozjr/conflata"
+)
+
+// Provider reads values from files in a directory, such as Kubernetes secrets
+// or Docker secrets mounted into the container. Each key names a file relative
+// to the directory.
+type Provider struct {
+	dir           string
+	watchInterval time.Duration
+	// stat reads file metadata for Watch; tests replace it to observe polls.
+	stat func(name string) (fs.FileInfo, error)
+}
+
+// Option configures the file provider.
+type Option func(*Provider)
+
+// WithWatchInterval sets how often Watch checks files for modifications.
+// Defaults to ten seconds.
+func WithWatchInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.watchInterval = d
+		}
+	}
+}
+
+// New constructs a provider rooted at dir.
+func New(dir string, opts ...Option) (*Provider, error) {
+	if dir == "" {
+		return nil, errors.New("file: directory is required")
+	}
+	p := &Provider{
+		dir:           dir,
+		watchInterval: 10 * time.Second,
+		stat:          os.Stat,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Fetch returns the contents of the file named by key.
+func (p *Provider) Fetch(ctx context.Context, key string) (string, error) {
+	value, _, err := p.FetchWithMetadata(ctx, key)
+	return value, err
+}
+
+// FetchWithMetadata returns the contents of the file named by key. The version
+// is derived from the file's modification time and size.
+func (p *Provider) FetchWithMetadata(_ context.Context, key string) (string, conflata.ValueMetadata, error) {
+	var meta conflata.ValueMetadata
+	path, err := p.path(key)
+	if err != nil {
+		return "", meta, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", meta, wrap(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", meta, wrap(err)
+	}
+	meta.Version = version(info)
+	meta.CreatedAt = info.ModTime()
+	if len(data) == 0 {
+		return "", meta, fmt.Errorf("file: %s is empty: %w", key, conflata.ErrEmptyValue)
+	}
+	return string(data), meta, nil
+}
+
+// Watch reports keys whose file was modified, created, or removed. Symlinks
+// are followed, so atomic swaps of Kubernetes secret volumes are detected. It
+// implements conflata.Watcher by polling every watch interval.
+func (p *Provider) Watch(ctx context.Context, keys []string) (<-chan string, error) {
+	return conflata.PollVersions(ctx, keys, p.watchInterval, p.currentVersion), nil
+}
+
+func (p *Provider) currentVersion(_ context.Context, key string) (string, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return "", err
+	}
+	info, err := p.stat(path)
+	if err != nil {
+		return "", wrap(err)
+	}
+	return version(info), nil
+}
+
+// path resolves key inside the provider directory, rejecting keys that would
+// escape it.
+func (p *Provider) path(key string) (string, error) {
+	if key == "" {
+		return "", errors.New("file: key cannot be empty")
+	}
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("file: key %q escapes the secret directory", key)
+	}
+	return filepath.Join(p.dir, key), nil
+}
+
+func version(info fs.FileInfo) string {
+	return strconv.FormatInt(info.ModTime().UnixNano(), 10) + "-" + strconv.FormatInt(info.Size(), 10)
+}
+
+func wrap(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("file: %w: %w", conflata.ErrNotFound, err)
+	case errors.Is(err, fs.ErrPermission):
+		return fmt.Errorf("file: %w: %w", conflata.ErrPermissionDenied, err)
+	default:
+		return fmt.Errorf("file: %w", err)
+	}
+}
@@ -0,0 +1,85 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/djbozjr/conflata"
+)
+
+func TestProviderFetch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := New(dir)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	value, meta, err := provider.FetchWithMetadata(context.Background(), "db-password")
+	if err != nil || value != "hunter2" {
+		t.Fatalf("unexpected fetch result %q, %v", value, err)
+	}
+	if meta.Version == "" || meta.CreatedAt.IsZero() {
+		t.Fatalf("expected version metadata, got %+v", meta)
+	}
+}
+
+func TestProviderMapsErrorsToSentinels(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "empty"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, _ := New(dir)
+	if _, err := provider.Fetch(context.Background(), "missing"); !errors.Is(err, conflata.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := provider.Fetch(context.Background(), "empty"); !errors.Is(err, conflata.ErrEmptyValue) {
+		t.Fatalf("expected ErrEmptyValue, got %v", err)
+	}
+	if _, err := provider.Fetch(context.Background(), "../etc/passwd"); err == nil {
+		t.Fatalf("expected keys outside the directory to be rejected")
+	}
+}
+
+func TestProviderWatchReportsModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("one"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, _ := New(dir, WithWatchInterval(5*time.Millisecond))
+	// The baseline poll stats both keys; it is complete after the second call.
+	baseline := make(chan struct{})
+	calls := 0
+	provider.stat = func(name string) (fs.FileInfo, error) {
+		calls++
+		if calls == 2 {
+			close(baseline)
+		}
+		return os.Stat(name)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := provider.Watch(ctx, []string{"token", "absent"})
+	if err != nil {
+		t.Fatalf("Watch error: %v", err)
+	}
+	<-baseline
+	if err := os.WriteFile(path, []byte("two!"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case key := <-changes:
+		if key != "token" {
+			t.Fatalf("expected token change, got %q", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for change")
+	}
+}
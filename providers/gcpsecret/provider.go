@@ -5,9 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/djbozjr/conflata"
 )
 
 // Client represents the subset of the GCP Secret Manager client used.
@@ -15,11 +20,21 @@ type Client interface {
 	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
 }
 
+// VersionGetter is implemented by Secret Manager clients that can look up a
+// secret version without accessing its payload. *secretmanager.Client
+// satisfies this interface; when the client passed to New implements it, Watch
+// resolves version aliases with GetSecretVersion instead of
+// AccessSecretVersion.
+type VersionGetter interface {
+	GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.SecretVersion, error)
+}
+
 // Provider fetches secrets from Google Secret Manager.
 type Provider struct {
-	client  Client
-	project string
-	version string
+	client        Client
+	project       string
+	version       string
+	watchInterval time.Duration
 }
 
 // Option configures the provider.
@@ -42,14 +57,25 @@ func WithVersion(version string) Option {
 	}
 }
 
+// WithWatchInterval sets how often Watch checks secrets for a new version.
+// Defaults to one minute.
+func WithWatchInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.watchInterval = d
+		}
+	}
+}
+
 // New constructs a Secret Manager provider.
 func New(client Client, opts ...Option) (*Provider, error) {
 	if client == nil {
 		return nil, errors.New("gcpsecret: client is required")
 	}
 	p := &Provider{
-		client:  client,
-		version: "latest",
+		client:        client,
+		version:       "latest",
+		watchInterval: time.Minute,
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -61,22 +87,112 @@ func New(client Client, opts ...Option) (*Provider, error) {
 // names (projects/*/secrets/*/versions/*) or shorthand secret IDs when a project
 // was provided via options.
 func (p *Provider) Fetch(ctx context.Context, key string) (string, error) {
+	value, _, err := p.FetchWithMetadata(ctx, key)
+	return value, err
+}
+
+// FetchWithMetadata retrieves the secret identified by key along with the
+// resolved version number and full resource name.
+func (p *Provider) FetchWithMetadata(ctx context.Context, key string) (string, conflata.ValueMetadata, error) {
+	var meta conflata.ValueMetadata
 	if key == "" {
-		return "", errors.New("gcpsecret: secret name cannot be empty")
+		return "", meta, errors.New("gcpsecret: secret name cannot be empty")
 	}
-	name := key
-	if !strings.HasPrefix(key, "projects/") {
-		if p.project == "" {
-			return "", errors.New("gcpsecret: project must be set when using short secret names")
-		}
-		name = fmt.Sprintf("projects/%s/secrets/%s/versions/%s", p.project, key, p.version)
+	name, err := p.resourceName(key)
+	if err != nil {
+		return "", meta, err
 	}
 	resp, err := p.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: name})
 	if err != nil {
-		return "", fmt.Errorf("gcpsecret: %w", err)
+		return "", meta, p.wrap(err)
+	}
+	if resolved := resp.GetName(); resolved != "" {
+		meta.Version = resolved[strings.LastIndex(resolved, "/")+1:]
+		meta.Attributes = map[string]string{"name": resolved}
 	}
 	if resp.GetPayload() == nil || len(resp.Payload.Data) == 0 {
-		return "", errors.New("gcpsecret: secret payload empty")
+		return "", meta, fmt.Errorf("gcpsecret: secret payload empty: %w", conflata.ErrEmptyValue)
+	}
+	return string(resp.Payload.Data), meta, nil
+}
+
+// Watch reports keys whose version alias (latest unless WithVersion was used)
+// now resolves to a different version. It implements conflata.Watcher by
+// polling every watch interval.
+func (p *Provider) Watch(ctx context.Context, keys []string) (<-chan string, error) {
+	return conflata.PollVersions(ctx, keys, p.watchInterval, p.currentVersion), nil
+}
+
+// currentVersion returns the version number key currently resolves to.
+func (p *Provider) currentVersion(ctx context.Context, key string) (string, error) {
+	getter, ok := p.client.(VersionGetter)
+	if !ok {
+		_, meta, err := p.FetchWithMetadata(ctx, key)
+		return meta.Version, err
+	}
+	name, err := p.resourceName(key)
+	if err != nil {
+		return "", err
+	}
+	version, err := getter.GetSecretVersion(ctx, &secretmanagerpb.GetSecretVersionRequest{Name: name})
+	if err != nil {
+		return "", p.wrap(err)
+	}
+	resolved := version.GetName()
+	return resolved[strings.LastIndex(resolved, "/")+1:], nil
+}
+
+// resourceName expands shorthand secret IDs into full version resource names.
+func (p *Provider) resourceName(key string) (string, error) {
+	if strings.HasPrefix(key, "projects/") {
+		return key, nil
+	}
+	if p.project == "" {
+		return "", errors.New("gcpsecret: project must be set when using short secret names")
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s/versions/%s", p.project, key, p.version), nil
+}
+
+// wrap prefixes err and attaches the matching conflata sentinel, if any.
+func (p *Provider) wrap(err error) error {
+	if kind := p.classify(err); kind != nil {
+		return fmt.Errorf("gcpsecret: %w: %w", kind, err)
+	}
+	return fmt.Errorf("gcpsecret: %w", err)
+}
+
+// classify maps gRPC status codes onto conflata sentinel errors. It returns nil
+// when the error does not fit a known category.
+func (p *Provider) classify(err error) error {
+	if p.IsTransient(err) {
+		return conflata.ErrTransient
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return conflata.ErrNotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return conflata.ErrPermissionDenied
+	default:
+		return nil
+	}
+}
+
+// IsTransient reports whether err carries a gRPC status that indicates a
+// temporary condition (Unavailable, DeadlineExceeded, or ResourceExhausted
+// quota throttling).
+func (p *Provider) IsTransient(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
 	}
-	return string(resp.Payload.Data), nil
 }
@@ -3,10 +3,15 @@ package gcpsecret
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/djbozjr/conflata"
 )
 
 type stubClient struct {
@@ -98,3 +103,90 @@ func TestProviderMissingPayload(t *testing.T) {
 		t.Fatal("expected payload error")
 	}
 }
+
+func TestProviderIsTransient(t *testing.T) {
+	provider, err := New(&stubClient{})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{status.Error(codes.Unavailable, "try again"), true},
+		{status.Error(codes.DeadlineExceeded, "slow"), true},
+		{status.Error(codes.NotFound, "missing"), false},
+		{errors.New("boom"), false},
+	}
+	for _, tc := range cases {
+		if got := provider.IsTransient(fmt.Errorf("gcpsecret: %w", tc.err)); got != tc.want {
+			t.Fatalf("IsTransient(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestProviderMapsErrorsToSentinels(t *testing.T) {
+	cases := []struct {
+		err  error
+		want error
+	}{
+		{status.Error(codes.NotFound, "missing"), conflata.ErrNotFound},
+		{status.Error(codes.PermissionDenied, "nope"), conflata.ErrPermissionDenied},
+		{status.Error(codes.Unavailable, "later"), conflata.ErrTransient},
+	}
+	for _, tc := range cases {
+		provider, err := New(&stubClient{err: tc.err}, WithProject("demo"))
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if _, err := provider.Fetch(context.Background(), "db"); !errors.Is(err, tc.want) {
+			t.Fatalf("expected %v to match %v", err, tc.want)
+		}
+	}
+	provider, _ := New(&stubClient{response: &secretmanagerpb.AccessSecretVersionResponse{}}, WithProject("demo"))
+	if _, err := provider.Fetch(context.Background(), "db"); !errors.Is(err, conflata.ErrEmptyValue) {
+		t.Fatalf("expected empty payload to match ErrEmptyValue, got %v", err)
+	}
+}
+
+func TestProviderFetchWithMetadata(t *testing.T) {
+	stub := &stubClient{
+		response: &secretmanagerpb.AccessSecretVersionResponse{
+			Name:    "projects/123/secrets/db/versions/9",
+			Payload: &secretmanagerpb.SecretPayload{Data: []byte("value")},
+		},
+	}
+	provider, err := New(stub, WithProject("demo"))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	_, meta, err := provider.FetchWithMetadata(context.Background(), "db")
+	if err != nil {
+		t.Fatalf("FetchWithMetadata error: %v", err)
+	}
+	if meta.Version != "9" || meta.Attributes["name"] != "projects/123/secrets/db/versions/9" {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+}
+
+type versionClient struct {
+	stubClient
+	lastGet *secretmanagerpb.GetSecretVersionRequest
+}
+
+func (v *versionClient) GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.SecretVersion, error) {
+	v.lastGet = req
+	return &secretmanagerpb.SecretVersion{Name: "projects/demo/secrets/db-password/versions/9"}, nil
+}
+
+func TestProviderCurrentVersionUsesGetSecretVersion(t *testing.T) {
+	client := &versionClient{}
+	provider, _ := New(client, WithProject("demo"))
+	version, err := provider.currentVersion(context.Background(), "db-password")
+	if err != nil || version != "9" {
+		t.Fatalf("expected version 9, got %q (%v)", version, err)
+	}
+	if client.lastGet.GetName() != "projects/demo/secrets/db-password/versions/latest" || client.lastRequest != nil {
+		t.Fatalf("expected only GetSecretVersion to be called, got %+v", client.lastGet)
+	}
+}
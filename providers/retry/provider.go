@@ -0,0 +1,189 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/djbozjr/conflata"
+)
+
+// Provider wraps another conflata.Provider and retries transient failures with
+// exponential backoff and jitter. Each failed attempt that is retried is
+// reported to the loader via conflata.RecordAttempt so it shows up in the
+// field's AttemptError list.
+type Provider struct {
+	next        conflata.Provider
+	maxAttempts int
+	initial     time.Duration
+	maxDelay    time.Duration
+	jitter      float64
+	maxElapsed  time.Duration
+	isTransient func(error) bool
+	sleep       func(context.Context, time.Duration) error
+}
+
+// Option configures the retry provider.
+type Option func(*Provider)
+
+// WithMaxAttempts caps the total number of calls per Fetch, including the
+// first one. Defaults to 3.
+func WithMaxAttempts(n int) Option {
+	return func(p *Provider) {
+		if n > 0 {
+			p.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the initial delay between attempts and the ceiling the
+// exponentially growing delay is clamped to. Defaults to 100ms and 5s.
+func WithBackoff(initial, maxDelay time.Duration) Option {
+	return func(p *Provider) {
+		if initial > 0 {
+			p.initial = initial
+		}
+		if maxDelay > 0 {
+			p.maxDelay = maxDelay
+		}
+	}
+}
+
+// WithJitter randomises each delay by up to the given fraction (0 to 1) so
+// concurrent callers do not retry in lockstep. Defaults to 0.5.
+func WithJitter(fraction float64) Option {
+	return func(p *Provider) {
+		if fraction >= 0 && fraction <= 1 {
+			p.jitter = fraction
+		}
+	}
+}
+
+// WithMaxElapsed bounds the total time spent in a single Fetch, including
+// backoff delays. Retries that would overrun the deadline are not attempted.
+func WithMaxElapsed(d time.Duration) Option {
+	return func(p *Provider) {
+		p.maxElapsed = d
+	}
+}
+
+// WithClassifier overrides which errors are retried. By default the wrapped
+// provider's conflata.TransientClassifier implementation is used, falling back
+// to conflata.IsTransient.
+func WithClassifier(fn func(error) bool) Option {
+	return func(p *Provider) {
+		if fn != nil {
+			p.isTransient = fn
+		}
+	}
+}
+
+// New wraps next with retry behaviour.
+func New(next conflata.Provider, opts ...Option) (*Provider, error) {
+	if next == nil {
+		return nil, errors.New("retry: provider is required")
+	}
+	p := &Provider{
+		next:        next,
+		maxAttempts: 3,
+		initial:     100 * time.Millisecond,
+		maxDelay:    5 * time.Second,
+		jitter:      0.5,
+		sleep:       sleep,
+	}
+	if classifier, ok := next.(conflata.TransientClassifier); ok {
+		p.isTransient = classifier.IsTransient
+	} else {
+		p.isTransient = conflata.IsTransient
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Fetch calls the wrapped provider, retrying transient errors.
+func (p *Provider) Fetch(ctx context.Context, key string) (string, error) {
+	value, _, err := p.FetchWithMetadata(ctx, key)
+	return value, err
+}
+
+// FetchWithMetadata is like Fetch but also returns the metadata reported by
+// the wrapped provider when it implements conflata.MetadataProvider.
+func (p *Provider) FetchWithMetadata(ctx context.Context, key string) (string, conflata.ValueMetadata, error) {
+	if p.maxElapsed > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.maxElapsed)
+		defer cancel()
+	}
+	delay := p.initial
+	for attempt := 1; ; attempt++ {
+		value, meta, err := p.fetchNext(ctx, key)
+		if err == nil {
+			return value, meta, nil
+		}
+		if attempt >= p.maxAttempts || !p.isTransient(err) {
+			return "", meta, err
+		}
+		wait := p.withJitter(delay)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return "", meta, err
+		}
+		conflata.RecordAttempt(ctx, fmt.Errorf("attempt %d: %w", attempt, err))
+		if sleepErr := p.sleep(ctx, wait); sleepErr != nil {
+			return "", meta, err
+		}
+		delay *= 2
+		if delay > p.maxDelay {
+			delay = p.maxDelay
+		}
+	}
+}
+
+func (p *Provider) fetchNext(ctx context.Context, key string) (string, conflata.ValueMetadata, error) {
+	if mp, ok := p.next.(conflata.MetadataProvider); ok {
+		return mp.FetchWithMetadata(ctx, key)
+	}
+	value, err := p.next.Fetch(ctx, key)
+	return value, conflata.ValueMetadata{}, err
+}
+
+// IsTransient reports whether err would be retried, so wrappers stacked on
+// top of this provider classify errors the same way.
+func (p *Provider) IsTransient(err error) bool {
+	return p.isTransient(err)
+}
+
+// Watch forwards to the wrapped provider when it implements conflata.Watcher
+// and returns errors.ErrUnsupported otherwise.
+func (p *Provider) Watch(ctx context.Context, keys []string) (<-chan string, error) {
+	watcher, ok := p.next.(conflata.Watcher)
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+	return watcher.Watch(ctx, keys)
+}
+
+func (p *Provider) withJitter(d time.Duration) time.Duration {
+	if p.jitter == 0 || d <= 0 {
+		return d
+	}
+	spread := time.Duration(float64(d) * p.jitter)
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + rand.N(spread+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
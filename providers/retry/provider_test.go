@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/djbozjr/conflata"
+)
+
+var errThrottled = errors.New("throttled")
+
+type flakyProvider struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyProvider) Fetch(ctx context.Context, key string) (string, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return "", f.err
+	}
+	return "value", nil
+}
+
+func (f *flakyProvider) IsTransient(err error) bool {
+	return errors.Is(err, errThrottled)
+}
+
+func newTestProvider(t *testing.T, next conflata.Provider, opts ...Option) (*Provider, *[]time.Duration) {
+	t.Helper()
+	p, err := New(next, opts...)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	var sleeps []time.Duration
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	return p, &sleeps
+}
+
+func TestProviderRetriesTransientErrors(t *testing.T) {
+	next := &flakyProvider{failures: 2, err: errThrottled}
+	p, sleeps := newTestProvider(t, next, WithBackoff(10*time.Millisecond, time.Second), WithJitter(0))
+	got, err := p.Fetch(context.Background(), "secret")
+	if err != nil || got != "value" {
+		t.Fatalf("Fetch = %q, %v", got, err)
+	}
+	if next.calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", next.calls)
+	}
+	if len(*sleeps) != 2 || (*sleeps)[0] != 10*time.Millisecond || (*sleeps)[1] != 20*time.Millisecond {
+		t.Fatalf("expected exponential backoff, got %v", *sleeps)
+	}
+}
+
+func TestProviderStopsOnPermanentError(t *testing.T) {
+	next := &flakyProvider{failures: 5, err: errors.New("access denied")}
+	p, _ := newTestProvider(t, next)
+	if _, err := p.Fetch(context.Background(), "secret"); err == nil {
+		t.Fatal("expected error")
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected no retries, got %d calls", next.calls)
+	}
+}
+
+func TestProviderHonoursMaxAttempts(t *testing.T) {
+	next := &flakyProvider{failures: 10, err: errThrottled}
+	p, _ := newTestProvider(t, next, WithMaxAttempts(4))
+	if _, err := p.Fetch(context.Background(), "secret"); !errors.Is(err, errThrottled) {
+		t.Fatalf("expected last error to surface, got %v", err)
+	}
+	if next.calls != 4 {
+		t.Fatalf("expected 4 calls, got %d", next.calls)
+	}
+}
+
+func TestProviderHonoursMaxElapsed(t *testing.T) {
+	next := &flakyProvider{failures: 10, err: errThrottled}
+	p, _ := newTestProvider(t, next,
+		WithMaxAttempts(10),
+		WithBackoff(time.Hour, time.Hour),
+		WithMaxElapsed(time.Second),
+	)
+	if _, err := p.Fetch(context.Background(), "secret"); err == nil {
+		t.Fatal("expected error")
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected deadline to prevent retries, got %d calls", next.calls)
+	}
+}
+
+func TestProviderJitterStaysInRange(t *testing.T) {
+	p, _ := newTestProvider(t, &flakyProvider{}, WithJitter(0.5))
+	for i := 0; i < 100; i++ {
+		d := p.withJitter(100 * time.Millisecond)
+		if d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("jittered delay %v out of range", d)
+		}
+	}
+}
+
+func TestProviderRecordsRetriesOnField(t *testing.T) {
+	type Config struct {
+		Token string `conflata:"provider:token"`
+	}
+	next := &flakyProvider{failures: 5, err: errThrottled}
+	p, _ := newTestProvider(t, next, WithMaxAttempts(3))
+	loader := conflata.New(conflata.WithProvider("aws", p))
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	group, ok := err.(*conflata.ErrorGroup)
+	if !ok {
+		t.Fatalf("expected ErrorGroup, got %v", err)
+	}
+	attempts := group.Fields()[0].Attempts
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %v", attempts)
+	}
+	if !strings.Contains(attempts[0].Error(), "attempt 1") || !strings.Contains(attempts[1].Error(), "attempt 2") {
+		t.Fatalf("expected numbered retries, got %v", attempts)
+	}
+	if attempts[0].Source != conflata.SourceProvider || attempts[0].Identifier != "aws:token" {
+		t.Fatalf("unexpected attempt metadata %+v", attempts[0])
+	}
+}
+
+func TestNewRequiresProvider(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("expected error when provider is nil")
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 
 	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/djbozjr/conflata"
 )
 
 // KV is the subset of the Vault KV v2 interface the provider depends on.
@@ -14,11 +19,20 @@ type KV interface {
 	Get(ctx context.Context, path string) (*vaultapi.KVSecret, error)
 }
 
+// MetadataReader is implemented by KV accessors that can read a secret's
+// metadata without its data. *vaultapi.KVv2 satisfies this interface; when the
+// accessor passed to New implements it, Watch polls metadata instead of
+// reading the secret.
+type MetadataReader interface {
+	GetMetadata(ctx context.Context, path string) (*vaultapi.KVMetadata, error)
+}
+
 // Provider loads secrets from a Vault KV v2 mount.
 type Provider struct {
-	kv       KV
-	field    string
-	explicit bool
+	kv            KV
+	field         string
+	explicit      bool
+	watchInterval time.Duration
 }
 
 // Option configures the Vault provider.
@@ -34,12 +48,22 @@ func WithField(field string) Option {
 	}
 }
 
+// WithWatchInterval sets how often Watch checks secrets for a new KV version.
+// Defaults to one minute.
+func WithWatchInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.watchInterval = d
+		}
+	}
+}
+
 // New creates a Vault provider using the given KV accessor.
 func New(kv KV, opts ...Option) (*Provider, error) {
 	if kv == nil {
 		return nil, errors.New("vault: KV accessor is required")
 	}
-	p := &Provider{kv: kv}
+	p := &Provider{kv: kv, watchInterval: time.Minute}
 	for _, opt := range opts {
 		opt(p)
 	}
@@ -60,27 +84,40 @@ func FromClient(client *vaultapi.Client, mountPath string, opts ...Option) (*Pro
 
 // Fetch retrieves the secret at the supplied path.
 func (p *Provider) Fetch(ctx context.Context, path string) (string, error) {
+	value, _, err := p.FetchWithMetadata(ctx, path)
+	return value, err
+}
+
+// FetchWithMetadata retrieves the secret at the supplied path along with its
+// KV version number and creation time.
+func (p *Provider) FetchWithMetadata(ctx context.Context, path string) (string, conflata.ValueMetadata, error) {
+	var meta conflata.ValueMetadata
 	if path == "" {
-		return "", errors.New("vault: secret path cannot be empty")
+		return "", meta, errors.New("vault: secret path cannot be empty")
 	}
 	secret, err := p.kv.Get(ctx, path)
 	if err != nil {
-		return "", fmt.Errorf("vault: %w", err)
+		return "", meta, p.wrap(err)
 	}
 	if secret == nil || secret.Data == nil {
-		return "", errors.New("vault: secret contained no data")
+		return "", meta, fmt.Errorf("vault: secret contained no data: %w", conflata.ErrNotFound)
 	}
-	return p.extract(secret.Data)
+	if vm := secret.VersionMetadata; vm != nil {
+		meta.Version = strconv.Itoa(vm.Version)
+		meta.CreatedAt = vm.CreatedTime
+	}
+	value, err := p.extract(secret.Data)
+	return value, meta, err
 }
 
 func (p *Provider) extract(data map[string]any) (string, error) {
 	if len(data) == 0 {
-		return "", errors.New("vault: secret data empty")
+		return "", fmt.Errorf("vault: secret data empty: %w", conflata.ErrEmptyValue)
 	}
 	if p.explicit {
 		value, ok := data[p.field]
 		if !ok {
-			return "", fmt.Errorf("vault: field %q not found", p.field)
+			return "", fmt.Errorf("vault: field %q: %w", p.field, conflata.ErrNotFound)
 		}
 		return asString(value, p.field)
 	}
@@ -103,6 +140,68 @@ func (p *Provider) extract(data map[string]any) (string, error) {
 	return string(buf), nil
 }
 
+// Watch reports paths whose current KV version changed. It implements
+// conflata.Watcher by polling every watch interval.
+func (p *Provider) Watch(ctx context.Context, paths []string) (<-chan string, error) {
+	return conflata.PollVersions(ctx, paths, p.watchInterval, p.currentVersion), nil
+}
+
+// currentVersion returns the current KV version number of path.
+func (p *Provider) currentVersion(ctx context.Context, path string) (string, error) {
+	reader, ok := p.kv.(MetadataReader)
+	if !ok {
+		_, meta, err := p.FetchWithMetadata(ctx, path)
+		return meta.Version, err
+	}
+	md, err := reader.GetMetadata(ctx, path)
+	if err != nil {
+		return "", p.wrap(err)
+	}
+	if md == nil {
+		return "", fmt.Errorf("vault: secret has no metadata: %w", conflata.ErrNotFound)
+	}
+	return strconv.Itoa(md.CurrentVersion), nil
+}
+
+// wrap prefixes err and attaches the matching conflata sentinel, if any.
+func (p *Provider) wrap(err error) error {
+	if kind := p.classify(err); kind != nil {
+		return fmt.Errorf("vault: %w: %w", kind, err)
+	}
+	return fmt.Errorf("vault: %w", err)
+}
+
+// classify maps Vault errors onto conflata sentinel errors. It returns nil when
+// the error does not fit a known category.
+func (p *Provider) classify(err error) error {
+	if errors.Is(err, vaultapi.ErrSecretNotFound) {
+		return conflata.ErrNotFound
+	}
+	if p.IsTransient(err) {
+		return conflata.ErrTransient
+	}
+	var respErr *vaultapi.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.StatusCode {
+		case http.StatusNotFound:
+			return conflata.ErrNotFound
+		case http.StatusForbidden, http.StatusUnauthorized:
+			return conflata.ErrPermissionDenied
+		}
+	}
+	return nil
+}
+
+// IsTransient reports whether err is a Vault HTTP response with a 429 or 5xx
+// status, such as rate limiting or a sealed/standby node.
+func (p *Provider) IsTransient(err error) bool {
+	var respErr *vaultapi.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	return respErr.StatusCode == http.StatusTooManyRequests || respErr.StatusCode >= http.StatusInternalServerError
+}
+
 func asString(value any, field string) (string, error) {
 	switch v := value.(type) {
 	case string:
@@ -3,9 +3,12 @@ package vault
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/djbozjr/conflata"
 )
 
 type stubKV struct {
@@ -84,3 +87,95 @@ func TestNewRequiresKV(t *testing.T) {
 		t.Fatal("expected error when KV is nil")
 	}
 }
+
+func TestProviderIsTransient(t *testing.T) {
+	provider, err := New(stubKV{})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&vaultapi.ResponseError{StatusCode: 503}, true},
+		{&vaultapi.ResponseError{StatusCode: 429}, true},
+		{&vaultapi.ResponseError{StatusCode: 403}, false},
+		{errors.New("boom"), false},
+	}
+	for _, tc := range cases {
+		if got := provider.IsTransient(fmt.Errorf("vault: %w", tc.err)); got != tc.want {
+			t.Fatalf("IsTransient(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestProviderMapsErrorsToSentinels(t *testing.T) {
+	cases := []struct {
+		err  error
+		want error
+	}{
+		{fmt.Errorf("%w: at secret/data/app", vaultapi.ErrSecretNotFound), conflata.ErrNotFound},
+		{&vaultapi.ResponseError{StatusCode: 403}, conflata.ErrPermissionDenied},
+		{&vaultapi.ResponseError{StatusCode: 502}, conflata.ErrTransient},
+	}
+	for _, tc := range cases {
+		provider, err := New(stubKV{err: tc.err})
+		if err != nil {
+			t.Fatalf("New returned error: %v", err)
+		}
+		if _, err := provider.Fetch(context.Background(), "app"); !errors.Is(err, tc.want) {
+			t.Fatalf("expected %v to match %v", err, tc.want)
+		}
+	}
+	secret := &vaultapi.KVSecret{Data: map[string]any{"other": "value"}}
+	provider, _ := New(stubKV{data: map[string]*vaultapi.KVSecret{"app": secret}}, WithField("password"))
+	if _, err := provider.Fetch(context.Background(), "app"); !errors.Is(err, conflata.ErrNotFound) {
+		t.Fatalf("expected missing field to match ErrNotFound, got %v", err)
+	}
+}
+
+func TestProviderFetchWithMetadata(t *testing.T) {
+	secret := &vaultapi.KVSecret{
+		Data:            map[string]any{"value": "demo"},
+		VersionMetadata: &vaultapi.KVVersionMetadata{Version: 4},
+	}
+	provider, err := New(stubKV{data: map[string]*vaultapi.KVSecret{"demo": secret}})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	got, meta, err := provider.FetchWithMetadata(context.Background(), "demo")
+	if err != nil || got != "demo" {
+		t.Fatalf("FetchWithMetadata = %q, %v", got, err)
+	}
+	if meta.Version != "4" {
+		t.Fatalf("expected version 4, got %+v", meta)
+	}
+}
+
+type metadataKV struct {
+	stubKV
+	current int
+}
+
+func (m metadataKV) GetMetadata(ctx context.Context, path string) (*vaultapi.KVMetadata, error) {
+	return &vaultapi.KVMetadata{CurrentVersion: m.current}, nil
+}
+
+var _ MetadataReader = (*vaultapi.KVv2)(nil)
+
+func TestProviderCurrentVersionUsesMetadata(t *testing.T) {
+	provider, _ := New(metadataKV{current: 7})
+	version, err := provider.currentVersion(context.Background(), "app/db")
+	if err != nil || version != "7" {
+		t.Fatalf("expected version 7, got %q (%v)", version, err)
+	}
+
+	secret := &vaultapi.KVSecret{
+		Data:            map[string]any{"value": "demo"},
+		VersionMetadata: &vaultapi.KVVersionMetadata{Version: 3},
+	}
+	provider, _ = New(stubKV{data: map[string]*vaultapi.KVSecret{"app/db": secret}})
+	if version, _ := provider.currentVersion(context.Background(), "app/db"); version != "3" {
+		t.Fatalf("expected fallback to the secret version, got %q", version)
+	}
+}
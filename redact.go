@@ -0,0 +1,130 @@
+package conflata
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedError is a decode error whose message has been scrubbed of the raw
+// value. It only unwraps to causes that cannot carry the value, such as
+// strconv.ErrSyntax.
+type redactedError struct {
+	msg   string
+	cause error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.cause
+}
+
+// yamlQuoted matches the back-quoted, possibly truncated value yaml.v3 puts in
+// type errors, with the space before it.
+var yamlQuoted = regexp.MustCompile(" `[^`]*`")
+
+// minRedactLength is the shortest raw value searched for as an unquoted
+// substring of an error message.
+const minRedactLength = 3
+
+// redactDecodeError rewrites err, produced while decoding raw into target, so
+// its message no longer contains raw or fragments of it. Errors from strconv,
+// encoding/json, encoding/xml, YAML and TOML are replaced by messages built
+// from their structured fields, keeping offsets, lines and columns. Errors
+// from a type's own UnmarshalText or UnmarshalJSON keep only the type, since
+// their wording is arbitrary, and errors from single delimited elements are
+// scrubbed against that element's text. Quoted occurrences of raw are
+// replaced by RedactedPlaceholder, and a message that still contains raw is
+// dropped entirely. The expected type is appended.
+func redactDecodeError(err error, raw string, target reflect.Type) error {
+	msg, cause := redactMessage(err, raw)
+	return &redactedError{
+		msg:   fmt.Sprintf("%s (decoding into %s)", msg, target),
+		cause: cause,
+	}
+}
+
+// redactMessage returns the scrubbed message of err, produced while decoding
+// raw, and the cause it may still unwrap to.
+func redactMessage(err error, raw string) (string, error) {
+	msg := err.Error()
+	var cause error
+	var (
+		elementErr   *elementError
+		unmarshalErr *unmarshalerError
+		numErr       *strconv.NumError
+		syntaxErr    *json.SyntaxError
+		typeErr      *json.UnmarshalTypeError
+		xmlErr       *xml.SyntaxError
+		yamlErr      *yaml.TypeError
+		tomlErr      toml.ParseError
+	)
+	switch {
+	case errors.As(err, &elementErr):
+		// Elements are decoded on their own, so their errors quote the
+		// element rather than raw.
+		inner, innerCause := redactMessage(elementErr.err, elementErr.raw)
+		msg = strings.Replace(msg, elementErr.Error(), "delimited decode: "+elementErr.position+": "+inner, 1)
+		cause = innerCause
+	case errors.As(err, &unmarshalErr):
+		// Checked first: a custom method may wrap any of the errors below
+		// inside a message of its own.
+		msg = strings.Replace(msg, unmarshalErr.Error(), fmt.Sprintf("%s decode: invalid %s", unmarshalErr.kind, unmarshalErr.target), 1)
+	case errors.As(err, &numErr):
+		msg = strings.Replace(msg, numErr.Error(), "strconv."+numErr.Func+": "+numErr.Err.Error(), 1)
+		cause = numErr.Err
+	case errors.As(err, &syntaxErr):
+		msg = strings.Replace(msg, syntaxErr.Error(), fmt.Sprintf("syntax error at offset %d", syntaxErr.Offset), 1)
+	case errors.As(err, &typeErr):
+		// Value is "number 1e999" for out-of-range numbers; keep the kind only.
+		kind := strings.Fields(typeErr.Value + " value")[0]
+		safe := fmt.Sprintf("cannot unmarshal %s into %s at offset %d", kind, typeErr.Type, typeErr.Offset)
+		if typeErr.Field != "" {
+			safe = fmt.Sprintf("cannot unmarshal %s into field %s of type %s at offset %d", kind, typeErr.Field, typeErr.Type, typeErr.Offset)
+		}
+		msg = strings.Replace(msg, typeErr.Error(), safe, 1)
+	case errors.As(err, &xmlErr):
+		msg = strings.Replace(msg, xmlErr.Error(), fmt.Sprintf("syntax error on line %d", xmlErr.Line), 1)
+	case errors.As(err, &yamlErr):
+		// Entries read "line 3: cannot unmarshal !!str `hunte...` into int".
+		safe := make([]string, len(yamlErr.Errors))
+		for i, entry := range yamlErr.Errors {
+			safe[i] = yamlQuoted.ReplaceAllString(entry, "")
+		}
+		msg = strings.Replace(msg, yamlErr.Error(), "yaml: "+strings.Join(safe, "; "), 1)
+	case errors.As(err, &tomlErr):
+		safe := fmt.Sprintf("toml: invalid value on line %d, column %d", tomlErr.Position.Line, tomlErr.Position.Col)
+		if tomlErr.LastKey != "" {
+			safe += fmt.Sprintf(" (last key %q)", tomlErr.LastKey)
+		}
+		msg = strings.Replace(msg, tomlErr.Error(), safe, 1)
+	}
+	return scrubRaw(msg, raw), cause
+}
+
+// scrubRaw replaces quoted occurrences of raw in msg by RedactedPlaceholder and
+// drops a message that still contains raw.
+func scrubRaw(msg, raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return msg
+	}
+	msg = strings.ReplaceAll(msg, strconv.Quote(trimmed), strconv.Quote(RedactedPlaceholder))
+	// Unquoted echoes cannot be cut out reliably, so the message is dropped.
+	// Values this short are too common as substrings to tell.
+	if len(trimmed) >= minRedactLength && strings.Contains(msg, trimmed) {
+		msg = "decoder rejected the value"
+	}
+	return msg
+}
@@ -0,0 +1,85 @@
+package conflata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoaderRedactsDecodeErrors(t *testing.T) {
+	type Payload struct {
+		Port int `json:"port" xml:"port"`
+	}
+	type Config struct {
+		Port    int           `conflata:"env:PORT"`
+		Timeout time.Duration `conflata:"env:TIMEOUT"`
+		Syntax  Payload       `conflata:"env:SYNTAX"`
+		Type    Payload       `conflata:"env:TYPE"`
+		XML     Payload       `conflata:"env:XML format:xml"`
+		Custom  string        `conflata:"env:CUSTOM format:echo"`
+		YAML    Payload       `conflata:"env:YAML format:yaml"`
+		TOML    Payload       `conflata:"env:TOML format:toml"`
+		Time    time.Time     `conflata:"env:TIME"`
+		Addr    netip.Addr    `conflata:"env:ADDR"`
+	}
+	env := map[string]string{
+		"PORT":    "hunter2",
+		"TIMEOUT": "hunter3",
+		"SYNTAX":  `{"port": hunter4}`,
+		"TYPE":    `{"port": "hunter5"}`,
+		"XML":     "<Payload><port hunter6></Payload>",
+		"CUSTOM":  "hunter7",
+		"YAML":    "port: hunter8",
+		"TOML":    "port = hunter9",
+		"TIME":    "2024-01-02T%hunter10secret",
+		"ADDR":    "10.0.0.1%hunter11",
+	}
+	echo := func(raw string, _ reflect.Type) (any, error) {
+		return nil, fmt.Errorf("cannot use %s here", raw)
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	var cfg Config
+	err := New(WithEnvLookup(lookup), WithDecoder("echo", echo)).Load(context.Background(), &cfg)
+	if err == nil {
+		t.Fatalf("expected decode errors")
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "hunter") {
+		t.Fatalf("decode errors leaked raw values: %s", msg)
+	}
+	for _, want := range []string{
+		"strconv.ParseInt: invalid syntax (decoding into int)",
+		"time.Duration",
+		"syntax error at offset",
+		"cannot unmarshal string into field port of type int at offset",
+		"syntax error on line 1",
+		"decoder rejected the value",
+		"yaml: line 1: cannot unmarshal !!str into int",
+		"toml: invalid value on line 1, column 8",
+		"text decode: invalid time.Time (decoding into time.Time)",
+		"text decode: invalid netip.Addr (decoding into netip.Addr)",
+		"decoder (PORT)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in %s", want, msg)
+		}
+	}
+	if !errors.Is(err, strconv.ErrSyntax) || !errors.Is(err, ErrDecode) {
+		t.Fatalf("expected safe causes to remain matchable: %v", err)
+	}
+
+	err = New(WithEnvLookup(lookup), WithVerboseDecodeErrors()).Load(context.Background(), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected verbose errors to keep raw values, got %v", err)
+	}
+}
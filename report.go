@@ -0,0 +1,163 @@
+package conflata
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Report describes where each resolved field obtained its value during a
+// LoadWithReport call. It marshals to JSON for debug endpoints and renders one
+// line per field via String for startup logs. Raw values are never included.
+type Report struct {
+	Fields []FieldReport `json:"fields"`
+}
+
+// FieldReport records the winning source for a single field together with the
+// attempts that failed before it.
+type FieldReport struct {
+	Path       string         `json:"path"`
+	Source     ValueSource    `json:"source"`
+	Identifier string         `json:"identifier"`
+	Backend    string         `json:"backend,omitempty"`
+	Key        string         `json:"key,omitempty"`
+	Selector   string         `json:"selector,omitempty"`
+	Format     string         `json:"format,omitempty"`
+	Transforms []string       `json:"transforms,omitempty"`
+	Shared     bool           `json:"shared,omitempty"`
+	Metadata   *ValueMetadata `json:"metadata,omitempty"`
+	Attempts   []AttemptError `json:"attempts,omitempty"`
+	// Deprecation is set when the value came from a deprecated alias.
+	Deprecation *DeprecationWarning `json:"deprecation,omitempty"`
+	// KeySource and KeyIdentifier name where the private key of a
+	// tls.Certificate field with keyenv or keyprovider was read.
+	KeySource     ValueSource `json:"keySource,omitempty"`
+	KeyIdentifier string      `json:"keyIdentifier,omitempty"`
+}
+
+// DeprecationWarning reports a field whose value was read from an environment
+// variable alias marked deprecated, as in `env:NEW_NAME,OLD_NAME:deprecated`.
+type DeprecationWarning struct {
+	FieldPath   string      `json:"path"`
+	Source      ValueSource `json:"source"`
+	Identifier  string      `json:"identifier"`
+	Replacement string      `json:"replacement"`
+}
+
+// String renders the warning for logs.
+func (w DeprecationWarning) String() string {
+	return fmt.Sprintf("%s: %s %s is deprecated, use %s instead", w.FieldPath, w.Source, w.Identifier, w.Replacement)
+}
+
+// deprecationOf returns the warning for a value resolved from src, or nil when
+// src is not a deprecated alias.
+func deprecationOf(fieldPath string, src valueSource) *DeprecationWarning {
+	env, ok := src.(envSource)
+	if !ok || env.replacement == "" {
+		return nil
+	}
+	return &DeprecationWarning{
+		FieldPath:   fieldPath,
+		Source:      SourceEnv,
+		Identifier:  env.key,
+		Replacement: env.replacement,
+	}
+}
+
+// Field returns the report entry for the given field path.
+func (r *Report) Field(path string) (FieldReport, bool) {
+	if r == nil {
+		return FieldReport{}, false
+	}
+	for _, field := range r.Fields {
+		if field.Path == path {
+			return field, true
+		}
+	}
+	return FieldReport{}, false
+}
+
+// Deprecations returns the warnings of every field resolved from a deprecated
+// alias, in field order.
+func (r *Report) Deprecations() []DeprecationWarning {
+	if r == nil {
+		return nil
+	}
+	var warnings []DeprecationWarning
+	for _, field := range r.Fields {
+		if field.Deprecation != nil {
+			warnings = append(warnings, *field.Deprecation)
+		}
+	}
+	return warnings
+}
+
+// String renders the report with one line per field.
+func (r *Report) String() string {
+	if r == nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, field := range r.Fields {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(field.String())
+	}
+	return b.String()
+}
+
+// String renders the field as "Path <- source (identifier)" followed by any
+// details that are available.
+func (f FieldReport) String() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%s <- %s (%s)", f.Path, f.Source, f.Identifier)
+	if f.Key != "" {
+		_, _ = fmt.Fprintf(&b, " key=%s", f.Key)
+	}
+	if f.Selector != "" {
+		_, _ = fmt.Fprintf(&b, " selector=%s", f.Selector)
+	}
+	if f.Format != "" {
+		_, _ = fmt.Fprintf(&b, " format=%s", f.Format)
+	}
+	if len(f.Transforms) > 0 {
+		_, _ = fmt.Fprintf(&b, " transform=%s", strings.Join(f.Transforms, ","))
+	}
+	if f.Metadata != nil && f.Metadata.Version != "" {
+		_, _ = fmt.Fprintf(&b, " version=%s", f.Metadata.Version)
+	}
+	if f.Shared {
+		b.WriteString(" shared")
+	}
+	if len(f.Attempts) > 0 {
+		_, _ = fmt.Fprintf(&b, " after %d failed attempt(s)", len(f.Attempts))
+	}
+	if f.Deprecation != nil {
+		_, _ = fmt.Fprintf(&b, " deprecated, use %s", f.Deprecation.Replacement)
+	}
+	return b.String()
+}
+
+// reportDescriber is implemented by value sources that can add details to the
+// report of a field they resolved.
+type reportDescriber interface {
+	describe(*FieldReport)
+}
+
+func (l *Loader) newFieldReport(path string, fieldType reflect.Type, tag fieldTag, source ValueSource, identifier string, attempts []AttemptError) FieldReport {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	report := FieldReport{
+		Path:       path,
+		Source:     source,
+		Identifier: identifier,
+		Format:     l.resolveFormat(fieldType, tag.decodeSpec()),
+		Transforms: tag.Transforms,
+	}
+	if len(attempts) > 0 {
+		report.Attempts = append([]AttemptError(nil), attempts...)
+	}
+	return report
+}
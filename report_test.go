@@ -0,0 +1,108 @@
+package conflata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type metadataProvider struct {
+	stubProvider
+	version string
+}
+
+func (m metadataProvider) FetchWithMetadata(ctx context.Context, key string) (string, ValueMetadata, error) {
+	value, err := m.Fetch(ctx, key)
+	return value, ValueMetadata{Version: m.version}, err
+}
+
+func TestLoadWithReportDescribesSources(t *testing.T) {
+	type Settings struct {
+		Enabled bool `json:"enabled"`
+	}
+	type Config struct {
+		DatabaseURL string        `conflata:"env:DATABASE_URL provider:db-url"`
+		APIKey      string        `conflata:"env:API_KEY provider:api-key backend:vault"`
+		Timeout     time.Duration `conflata:"env:TIMEOUT default:5s"`
+		Settings    Settings      `conflata:"provider:api-key backend:vault"`
+	}
+	env := map[string]string{"DATABASE_URL": "postgres://env"}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		}),
+		WithProvider("vault", metadataProvider{
+			stubProvider: stubProvider{values: map[string]providerResponse{
+				"prd/api-key": {value: `{"enabled":true}`},
+			}},
+			version: "7",
+		}),
+		WithProviderPrefix(func() string { return "prd/" }),
+	)
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithReport returned error: %v", err)
+	}
+	if len(report.Fields) != 4 {
+		t.Fatalf("expected 4 field reports, got %+v", report.Fields)
+	}
+
+	db, _ := report.Field("DatabaseURL")
+	if db.Source != SourceEnv || db.Identifier != "DATABASE_URL" || len(db.Attempts) != 0 {
+		t.Fatalf("unexpected DatabaseURL report %+v", db)
+	}
+	api, _ := report.Field("APIKey")
+	if api.Source != SourceProvider || api.Backend != "vault" || api.Key != "prd/api-key" {
+		t.Fatalf("unexpected APIKey report %+v", api)
+	}
+	if api.Metadata == nil || api.Metadata.Version != "7" {
+		t.Fatalf("expected provider metadata, got %+v", api.Metadata)
+	}
+	if len(api.Attempts) != 1 || api.Attempts[0].Source != SourceEnv || !errors.Is(api.Attempts[0], ErrNotFound) {
+		t.Fatalf("expected failed env attempt, got %+v", api.Attempts)
+	}
+	timeout, _ := report.Field("Timeout")
+	if timeout.Source != SourceDefault || timeout.Identifier != "default" {
+		t.Fatalf("unexpected Timeout report %+v", timeout)
+	}
+	settings, _ := report.Field("Settings")
+	if settings.Format != "json" || !settings.Shared {
+		t.Fatalf("expected shared json fetch, got %+v", settings)
+	}
+
+	line := api.String()
+	if !strings.Contains(line, "APIKey <- provider (vault:api-key)") || !strings.Contains(line, "version=7") {
+		t.Fatalf("unexpected report line %q", line)
+	}
+	buf, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal report: %v", err)
+	}
+	if !strings.Contains(string(buf), `"error":"not set"`) || strings.Contains(string(buf), "enabled") {
+		t.Fatalf("unexpected report JSON %s", buf)
+	}
+}
+
+func TestLoadWithReportReturnsPartialReport(t *testing.T) {
+	type Config struct {
+		Name  string `conflata:"default:svc"`
+		Token string `conflata:"env:TOKEN"`
+	}
+	loader := New(WithEnvLookup(func(string) (string, bool) { return "", false }))
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("expected error for missing token")
+	}
+	if _, ok := report.Field("Name"); !ok {
+		t.Fatalf("expected report for resolved field, got %+v", report)
+	}
+	if _, ok := report.Field("Token"); ok {
+		t.Fatal("did not expect report for unresolved field")
+	}
+}
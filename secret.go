@@ -0,0 +1,132 @@
+package conflata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedPlaceholder is what a Secret prints instead of its value.
+const RedactedPlaceholder = "[REDACTED]"
+
+// Secret holds a sensitive value of type T. The loader decodes into it
+// transparently, using the same rules (tags, formats, unmarshalers) as a plain
+// T field, while fmt, encoding/json, encoding and log/slog only ever see
+// RedactedPlaceholder. Use Reveal to read the value.
+//
+// Secret only protects values it can intercept: a Secret stored in an
+// unexported field is printed field by field by fmt like any other struct.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret wraps value in a Secret.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Reveal returns the wrapped value.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// String implements fmt.Stringer.
+func (s Secret[T]) String() string {
+	return RedactedPlaceholder
+}
+
+// GoString implements fmt.GoStringer.
+func (s Secret[T]) GoString() string {
+	return RedactedPlaceholder
+}
+
+// Format implements fmt.Formatter so every verb, including %#v and %x, prints
+// the placeholder.
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = io.WriteString(f, strconv.Quote(RedactedPlaceholder))
+		return
+	}
+	_, _ = io.WriteString(f, RedactedPlaceholder)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedPlaceholder)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(RedactedPlaceholder), nil
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(RedactedPlaceholder)
+}
+
+// UnmarshalJSON decodes data into the wrapped value, so Secret fields inside
+// JSON payloads decoded by the loader are populated too.
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.value)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for Secret fields inside YAML
+// payloads.
+func (s *Secret[T]) UnmarshalYAML(node *yaml.Node) error {
+	return node.Decode(&s.value)
+}
+
+// UnmarshalTOML implements toml.Unmarshaler for Secret fields inside TOML
+// payloads. The value is converted through JSON.
+func (s *Secret[T]) UnmarshalTOML(data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, &s.value)
+}
+
+func (s *Secret[T]) secretType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (s *Secret[T]) setSecret(value reflect.Value) {
+	s.value = value.Interface().(T)
+}
+
+// secretHolder is implemented by *Secret[T] so the loader can decode into the
+// wrapped type without knowing T.
+type secretHolder interface {
+	secretType() reflect.Type
+	setSecret(reflect.Value)
+}
+
+var secretHolderType = reflect.TypeFor[secretHolder]()
+
+// newSecretHolder returns a fresh *Secret[T] when t is a Secret type.
+func newSecretHolder(t reflect.Type) (secretHolder, bool) {
+	if !reflect.PointerTo(t).Implements(secretHolderType) {
+		return nil, false
+	}
+	return reflect.New(t).Interface().(secretHolder), true
+}
+
+// unwrapSecret returns the type decoded for t, looking through Secret and a
+// pointer wrapped by it.
+func unwrapSecret(t reflect.Type) reflect.Type {
+	holder, ok := newSecretHolder(t)
+	if !ok {
+		return t
+	}
+	inner := holder.secretType()
+	if inner.Kind() == reflect.Pointer {
+		inner = inner.Elem()
+	}
+	return inner
+}
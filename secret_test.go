@@ -0,0 +1,98 @@
+package conflata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecretRedactsOutput(t *testing.T) {
+	type Config struct {
+		User     string
+		Password Secret[string]
+		Pin      *Secret[int]
+	}
+	pin := NewSecret(1234)
+	cfg := Config{User: "app", Password: NewSecret("hunter2"), Pin: &pin}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		out := fmt.Sprintf(format, cfg.Password) + fmt.Sprintf(format, cfg)
+		if strings.Contains(out, "hunter2") || strings.Contains(out, "68756e74657232") {
+			t.Fatalf("%s leaked the secret: %s", format, out)
+		}
+	}
+	if got := fmt.Sprintf("%v", *cfg.Pin); got != RedactedPlaceholder {
+		t.Fatalf("unexpected formatting: %q", got)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "1234") {
+		t.Fatalf("JSON leaked the secret: %s", data)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("loaded", "password", cfg.Password)
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), RedactedPlaceholder) {
+		t.Fatalf("slog leaked the secret: %s", buf.String())
+	}
+
+	if cfg.Password.Reveal() != "hunter2" || cfg.Pin.Reveal() != 1234 {
+		t.Fatalf("Reveal returned the wrong value")
+	}
+}
+
+func TestLoaderDecodesIntoSecrets(t *testing.T) {
+	type Credentials struct {
+		User     string         `json:"user"`
+		Password Secret[string] `json:"password"`
+	}
+	type Config struct {
+		Token   Secret[string]         `conflata:"env:TOKEN"`
+		Port    Secret[int]            `conflata:"env:PORT"`
+		Timeout *Secret[time.Duration] `conflata:"env:TIMEOUT"`
+		Creds   Secret[Credentials]    `conflata:"env:CREDS"`
+		Inner   Credentials            `conflata:"env:INNER"`
+		Level   Secret[testLevel]      `conflata:"env:LEVEL"`
+		Missing Secret[string]         `conflata:"env:MISSING optional"`
+		Bad     Secret[int]            `conflata:"env:BAD"`
+	}
+	env := map[string]string{
+		"TOKEN":   "abc",
+		"PORT":    "8080",
+		"TIMEOUT": "5s",
+		"CREDS":   `{"user":"app","password":"pw"}`,
+		"INNER":   `{"user":"app","password":"pw2"}`,
+		"LEVEL":   "high",
+		"BAD":     "nope",
+	}
+	loader := New(WithEnvLookup(func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}))
+	var cfg Config
+	err := loader.Load(context.Background(), &cfg)
+	group, ok := err.(*ErrorGroup)
+	if !ok || len(group.Fields()) != 1 || group.Fields()[0].FieldPath != "Bad" {
+		t.Fatalf("expected only Bad to fail, got %v", err)
+	}
+	if cfg.Token.Reveal() != "abc" || cfg.Port.Reveal() != 8080 || cfg.Timeout.Reveal() != 5*time.Second {
+		t.Fatalf("unexpected primitives: %v %v %v", cfg.Token.Reveal(), cfg.Port.Reveal(), cfg.Timeout.Reveal())
+	}
+	if creds := cfg.Creds.Reveal(); creds.User != "app" || creds.Password.Reveal() != "pw" {
+		t.Fatalf("unexpected credentials: %+v", creds)
+	}
+	if cfg.Inner.Password.Reveal() != "pw2" {
+		t.Fatalf("expected nested Secret in JSON payload to decode")
+	}
+	if cfg.Level.Reveal() != 2 || cfg.Missing.Reveal() != "" {
+		t.Fatalf("unexpected level or missing value")
+	}
+}
@@ -0,0 +1,101 @@
+package conflata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// splitSelector separates a provider key such as "prod/db#password" into the
+// key fetched from the provider and the selector applied to its payload.
+func splitSelector(key string) (string, string) {
+	base, selector, found := strings.Cut(key, "#")
+	if !found {
+		return key, ""
+	}
+	return base, selector
+}
+
+// selectFragment extracts the scalar addressed by selector from a JSON payload.
+// A selector starting with "/" is a JSON Pointer (RFC 6901); anything else names
+// a top-level member. Missing members are reported as ErrNotFound, and
+// payloads that are not a single JSON document or selections that are not
+// scalars as ErrDecode.
+// Error messages never include the payload.
+func selectFragment(payload, selector string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return "", classify(fmt.Errorf("selector %q: payload is not a JSON document", selector), ErrDecode)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", classify(fmt.Errorf("selector %q: payload has data after the JSON document", selector), ErrDecode)
+	}
+
+	tokens := []string{selector}
+	if strings.HasPrefix(selector, "/") {
+		tokens = strings.Split(selector[1:], "/")
+		for i, token := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		}
+	}
+	current := doc
+	for i, token := range tokens {
+		at := "/" + strings.Join(tokens[:i], "/")
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[token]
+			if !ok {
+				return "", classify(fmt.Errorf("selector %q: no member %q at %s", selector, token, at), ErrNotFound)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || strconv.Itoa(index) != token {
+				return "", classify(fmt.Errorf("selector %q: %q is not an array index at %s", selector, token, at), ErrNotFound)
+			}
+			if index >= len(node) {
+				return "", classify(fmt.Errorf("selector %q: index %d out of range at %s", selector, index, at), ErrNotFound)
+			}
+			current = node[index]
+		default:
+			return "", classify(fmt.Errorf("selector %q: %s at %s has no member %q", selector, jsonKind(current), at, token), ErrNotFound)
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		if value == "" {
+			return "", classify(fmt.Errorf("selector %q: value is empty", selector), ErrEmptyValue)
+		}
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case nil:
+		return "", classify(fmt.Errorf("selector %q: value is null", selector), ErrNotFound)
+	default:
+		return "", classify(fmt.Errorf("selector %q: selects %s, not a scalar", selector, jsonKind(current)), ErrDecode)
+	}
+}
+
+func jsonKind(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return "null"
+	}
+}
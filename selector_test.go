@@ -0,0 +1,54 @@
+package conflata
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSelectFragment(t *testing.T) {
+	payload := `{"username":"app","port":5432,"tls":true,"creds":{"user":"admin","a/b":"slash","hosts":["h1","h2"]},"none":null,"empty":""}`
+	for selector, want := range map[string]string{
+		"username":       "app",
+		"port":           "5432",
+		"tls":            "true",
+		"/creds/user":    "admin",
+		"/creds/a~1b":    "slash",
+		"/creds/hosts/1": "h2",
+	} {
+		got, err := selectFragment(payload, selector)
+		if err != nil || got != want {
+			t.Fatalf("selectFragment(%q) = %q, %v; want %q", selector, got, err, want)
+		}
+	}
+	for selector, kind := range map[string]error{
+		"password":        ErrNotFound,
+		"/creds/missing":  ErrNotFound,
+		"/creds/hosts/2":  ErrNotFound,
+		"/creds/hosts/01": ErrNotFound,
+		"/username/first": ErrNotFound,
+		"none":            ErrNotFound,
+		"empty":           ErrEmptyValue,
+		"creds":           ErrDecode,
+		"/creds/hosts":    ErrDecode,
+	} {
+		_, err := selectFragment(payload, selector)
+		if !errors.Is(err, kind) {
+			t.Fatalf("selectFragment(%q) error = %v, want %v", selector, err, kind)
+		}
+		if strings.Contains(err.Error(), "admin") {
+			t.Fatalf("selectFragment(%q) error leaks payload: %v", selector, err)
+		}
+	}
+	if _, err := selectFragment("hunter2", "password"); !errors.Is(err, ErrDecode) || strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected redacted decode error for non-JSON payload, got %v", err)
+	}
+	for _, payload := range []string{`{"a":1} hunter2`, `{"a":1}{"a":2}`} {
+		if _, err := selectFragment(payload, "a"); !errors.Is(err, ErrDecode) || strings.Contains(err.Error(), "hunter2") {
+			t.Fatalf("expected redacted decode error for trailing data in %q, got %v", payload, err)
+		}
+	}
+	if got, err := selectFragment("{\"a\":1}\n", "a"); err != nil || got != "1" {
+		t.Fatalf("expected trailing whitespace to be accepted, got %q (%v)", got, err)
+	}
+}
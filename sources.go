@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 )
 
 type envSource struct {
 	key    string
 	lookup EnvLookupFunc
+	// replacement is set for deprecated aliases and names the variable to use
+	// instead.
+	replacement string
 }
 
 func (e envSource) Source() ValueSource {
@@ -23,12 +27,27 @@ func (e envSource) Fetch(context.Context) (string, error) {
 	if value, ok := e.lookup(e.key); ok {
 		return value, nil
 	}
-	return "", errors.New("not set")
+	return "", classify(errors.New("not set"), ErrNotFound)
 }
 
 type providerSource struct {
 	identifier string
-	fetchFunc  func(context.Context) (string, error)
+	backend    string
+	key        string
+	selector   string
+	// chained marks every backend of a fallback chain after the first. It is
+	// only consulted when the previous backend failed in a way that allows
+	// falling through.
+	chained   bool
+	outcome   *providerOutcome
+	fetchFunc func(context.Context) (string, error)
+}
+
+// providerOutcome captures details of the last fetch made through a
+// providerSource so they can be surfaced in a Report.
+type providerOutcome struct {
+	metadata *ValueMetadata
+	shared   bool
 }
 
 func (p providerSource) Source() ValueSource {
@@ -43,25 +62,116 @@ func (p providerSource) Fetch(ctx context.Context) (string, error) {
 	return p.fetchFunc(ctx)
 }
 
-func (l *Loader) sourcesFor(tag fieldTag) []valueSource {
-	var sources []valueSource
-	if tag.EnvKey != "" {
-		sources = append(sources, envSource{
-			key:    tag.EnvKey,
-			lookup: l.envLookup,
-		})
+func (p providerSource) describe(report *FieldReport) {
+	report.Backend = p.backend
+	report.Key = p.key
+	report.Selector = p.selector
+	if p.outcome != nil {
+		report.Metadata = p.outcome.metadata
+		report.Shared = p.outcome.shared
+	}
+}
+
+// defaultSource supplies the literal from a `default:` tag key.
+type defaultSource struct {
+	value string
+}
+
+func (d defaultSource) Source() ValueSource {
+	return SourceDefault
+}
+
+func (d defaultSource) Identifier() string {
+	return "default"
+}
+
+func (d defaultSource) Fetch(context.Context) (string, error) {
+	return d.value, nil
+}
+
+// defaultSourceOrder is the resolution order used unless WithSourceOrder or an
+// `order:` tag says otherwise.
+var defaultSourceOrder = []ValueSource{SourceEnv, SourceProvider, SourceDefault}
+
+// sourcesFor returns the sources configured by tag in resolution order: the
+// tag's `order:` key if present, otherwise the loader's source order. Source
+// classes disabled with WithDisabledSources are left out.
+func (l *Loader) sourcesFor(run *loadRun, tag fieldTag) []valueSource {
+	order := l.sourceOrder
+	if len(tag.Order) > 0 {
+		order = tag.Order
 	}
-	if tag.ProviderKey != "" {
-		sources = append(sources, l.newProviderSource(tag))
+	var sources []valueSource
+	for _, class := range order {
+		if l.disabledSources[class] {
+			continue
+		}
+		switch {
+		case class == SourceEnv && tag.EnvKey != "":
+			sources = append(sources, envSource{
+				key:    tag.EnvKey,
+				lookup: l.envLookup,
+			})
+			for _, alias := range tag.EnvAliases {
+				src := envSource{key: alias.Name, lookup: l.envLookup}
+				if alias.Deprecated {
+					src.replacement = tag.EnvKey
+				}
+				sources = append(sources, src)
+			}
+		case class == SourceProvider && tag.ProviderKey != "":
+			for i, backend := range l.backendsFor(tag) {
+				src := l.newProviderSource(run, tag, backend)
+				src.chained = i > 0
+				sources = append(sources, src)
+			}
+		case class == SourceDefault && tag.HasDefault:
+			sources = append(sources, defaultSource{value: tag.DefaultValue})
+		}
 	}
 	return sources
 }
 
-func (l *Loader) newProviderSource(tag fieldTag) valueSource {
-	backendName := tag.BackendName
-	if backendName == "" {
-		backendName = l.defaultProvider
+// orderableSource reports whether class can appear in a source order.
+func orderableSource(class ValueSource) bool {
+	return class == SourceEnv || class == SourceProvider || class == SourceDefault
+}
+
+// backendsFor returns the backends tried, in order, for tag's provider key. The
+// `backend:` key (or the default provider) may list several comma-separated
+// names, and names registered with WithProviderChain expand to their members.
+func (l *Loader) backendsFor(tag fieldTag) []string {
+	names := tag.BackendName
+	if names == "" {
+		names = l.defaultProvider
 	}
+	var backends []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		members, ok := l.chains[name]
+		if !ok {
+			members = []string{name}
+		}
+		for _, member := range members {
+			if !seen[member] {
+				seen[member] = true
+				backends = append(backends, member)
+			}
+		}
+	}
+	return backends
+}
+
+// fallsThrough reports whether a provider failure lets the next backend of a
+// fallback chain be tried.
+func (l *Loader) fallsThrough(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrProviderNotRegistered) ||
+		(l.transientFallthrough && errors.Is(err, ErrTransient))
+}
+
+func (l *Loader) newProviderSource(run *loadRun, tag fieldTag, backendName string) providerSource {
 	identifier := backendName
 	if identifier == "" {
 		identifier = "(default)"
@@ -71,27 +181,94 @@ func (l *Loader) newProviderSource(tag fieldTag) valueSource {
 		return providerSource{
 			identifier: identifier,
 			fetchFunc: func(context.Context) (string, error) {
-				return "", errors.New("provider not registered")
+				return "", ErrProviderNotRegistered
 			},
 		}
 	}
-	fullIdentifier := identifier + ":" + tag.ProviderKey
+	// The selector is applied to the fetched payload, so fields selecting
+	// different parts of one secret share a single fetch.
+	providerKey, selector := splitSelector(tag.ProviderKey)
+	key := fetchKey{
+		backend: strings.ToLower(backendName),
+		key:     l.decorateKey(providerKey),
+	}
+	outcome := &providerOutcome{}
 	return providerSource{
-		identifier: fullIdentifier,
+		identifier: identifier + ":" + tag.ProviderKey,
+		backend:    key.backend,
+		key:        key.key,
+		selector:   selector,
+		outcome:    outcome,
 		fetchFunc: func(ctx context.Context) (string, error) {
-			key := l.decorateKey(tag.ProviderKey)
-			raw, err := provider.Fetch(ctx, key)
-			if err != nil {
-				return "", err
-			}
-			if raw == "" {
-				return "", errors.New("empty secret")
+			res, shared := l.fetch(ctx, run, provider, key)
+			outcome.metadata = res.metadata
+			outcome.shared = shared
+			if res.err != nil || selector == "" {
+				return res.value, res.err
 			}
-			return raw, nil
+			return selectFragment(res.value, selector)
 		},
 	}
 }
 
+// fetch calls the provider for key, coalescing identical lookups within the
+// current Load and, when enabled, across concurrent Loads on the same Loader.
+// Attempts the provider recorded are replayed to every field sharing the
+// lookup.
+func (l *Loader) fetch(ctx context.Context, run *loadRun, provider Provider, key fetchKey) (fetchResult, bool) {
+	var shared bool
+	call := func(ctx context.Context) fetchResult {
+		var res fetchResult
+		ctx = withAttemptRecorder(ctx, func(err error) {
+			res.attempts = append(res.attempts, err)
+		})
+		if mp, ok := provider.(MetadataProvider); ok {
+			var meta ValueMetadata
+			res.value, meta, res.err = mp.FetchWithMetadata(ctx, key.key)
+			if res.err == nil && !meta.isZero() {
+				res.metadata = &meta
+			}
+		} else {
+			res.value, res.err = provider.Fetch(ctx, key.key)
+		}
+		if res.err == nil && res.value == "" {
+			res.err = classify(errors.New("empty secret"), ErrEmptyValue)
+		}
+		return res
+	}
+	if l.inflight != nil {
+		direct := call
+		call = func(ctx context.Context) fetchResult {
+			res, joined := l.inflight.do(ctx, key, direct)
+			shared = joined
+			return res
+		}
+	}
+
+	start := time.Now()
+	var res fetchResult
+	if run != nil && run.fetches != nil {
+		var memoised bool
+		res, memoised = run.fetches.do(ctx, key, call)
+		shared = shared || memoised
+	} else {
+		res = call(ctx)
+	}
+	for _, attempt := range res.attempts {
+		RecordAttempt(ctx, attempt)
+	}
+	if l.fetchHook != nil {
+		l.fetchHook(FetchEvent{
+			Backend:  key.backend,
+			Key:      key.key,
+			Shared:   shared,
+			Duration: time.Since(start),
+			Err:      res.err,
+		})
+	}
+	return res, shared
+}
+
 func (l *Loader) decorateKey(key string) string {
 	if key == "" {
 		return ""
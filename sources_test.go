@@ -3,7 +3,11 @@ package conflata
 import (
 	"context"
 	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type fakeProvider struct {
@@ -28,7 +32,7 @@ func TestSourcesForEnvAndProvider(t *testing.T) {
 		ProviderKey: "bar",
 		BackendName: "vault",
 	}
-	sources := loader.sourcesFor(tag)
+	sources := loader.sourcesFor(nil, tag)
 	if len(sources) != 2 {
 		t.Fatalf("expected 2 sources, got %d", len(sources))
 	}
@@ -43,7 +47,7 @@ func TestSourcesForEnvAndProvider(t *testing.T) {
 func TestProviderSourceHandlesMissingProvider(t *testing.T) {
 	loader := New()
 	tag := fieldTag{ProviderKey: "secret", BackendName: "missing"}
-	src := loader.newProviderSource(tag)
+	src := loader.newProviderSource(nil, tag, tag.BackendName)
 	if _, err := src.Fetch(context.Background()); err == nil {
 		t.Fatal("expected error when provider missing")
 	}
@@ -53,7 +57,7 @@ func TestProviderSourceEmptySecret(t *testing.T) {
 	loader := New()
 	loader.providers["vault"] = fakeProvider{value: ""}
 	tag := fieldTag{ProviderKey: "secret", BackendName: "vault"}
-	src := loader.newProviderSource(tag)
+	src := loader.newProviderSource(nil, tag, tag.BackendName)
 	if _, err := src.Fetch(context.Background()); err == nil {
 		t.Fatal("expected error for empty secret payload")
 	}
@@ -63,8 +67,238 @@ func TestProviderSourcePropagatesError(t *testing.T) {
 	loader := New()
 	loader.providers["vault"] = fakeProvider{err: errors.New("boom")}
 	tag := fieldTag{ProviderKey: "secret", BackendName: "vault"}
-	src := loader.newProviderSource(tag)
+	src := loader.newProviderSource(nil, tag, tag.BackendName)
 	if _, err := src.Fetch(context.Background()); err == nil {
 		t.Fatal("expected provider error to surface")
 	}
 }
+
+type countingProvider struct {
+	mu    sync.Mutex
+	calls map[string]int
+	value string
+}
+
+func (c *countingProvider) Fetch(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[key]++
+	return c.value, nil
+}
+
+func TestLoaderDeduplicatesFetchesWithinLoad(t *testing.T) {
+	type Credentials struct {
+		Username string `json:"username"`
+	}
+	type Config struct {
+		Creds    Credentials       `conflata:"provider:db"`
+		Raw      map[string]string `conflata:"provider:db"`
+		Fallback string            `conflata:"provider:db backend:AWS"`
+	}
+	provider := &countingProvider{value: `{"username":"app"}`}
+	var (
+		mu     sync.Mutex
+		events []FetchEvent
+	)
+	loader := New(
+		WithProvider("aws", provider),
+		WithConcurrency(3),
+		WithFetchHook(func(ev FetchEvent) {
+			mu.Lock()
+			events = append(events, ev)
+			mu.Unlock()
+		}),
+	)
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Creds.Username != "app" || cfg.Raw["username"] != "app" || cfg.Fallback == "" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if provider.calls["db"] != 1 {
+		t.Fatalf("expected a single fetch, got %d", provider.calls["db"])
+	}
+	shared := 0
+	for _, ev := range events {
+		if ev.Backend != "aws" || ev.Key != "db" {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+		if ev.Shared {
+			shared++
+		}
+	}
+	if len(events) != 3 || shared != 2 {
+		t.Fatalf("expected 3 events with 2 shared, got %+v", events)
+	}
+
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("second Load returned error: %v", err)
+	}
+	if provider.calls["db"] != 2 {
+		t.Fatalf("expected memo to reset between loads, got %d fetches", provider.calls["db"])
+	}
+}
+
+func TestLoaderSharesFetchesAcrossConcurrentLoads(t *testing.T) {
+	type Config struct {
+		Token string `conflata:"provider:token"`
+	}
+	provider := blockingProvider{release: make(chan struct{}), active: make(chan struct{}, 2)}
+	var shared atomic.Int32
+	loader := New(
+		WithProvider("aws", provider),
+		WithSharedFetches(),
+		WithFetchHook(func(ev FetchEvent) {
+			if ev.Shared {
+				shared.Add(1)
+			}
+		}),
+	)
+
+	var (
+		wg   sync.WaitGroup
+		cfgs [2]Config
+		errs [2]error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = loader.Load(context.Background(), &cfgs[0])
+	}()
+	<-provider.active
+	joined := newJoinSignalContext(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[1] = loader.Load(joined, &cfgs[1])
+	}()
+	select {
+	case <-joined.waiting:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second Load never joined the in-flight fetch")
+	}
+	close(provider.release)
+	wg.Wait()
+	for i := range cfgs {
+		if errs[i] != nil || cfgs[i].Token != "token-value" {
+			t.Fatalf("load %d: cfg=%+v err=%v", i, cfgs[i], errs[i])
+		}
+	}
+	if len(provider.active) != 0 {
+		t.Fatal("expected a single provider fetch")
+	}
+	if shared.Load() != 1 {
+		t.Fatalf("expected one shared fetch event, got %d", shared.Load())
+	}
+}
+
+func TestLoaderRetriesSharedFetchAfterLeaderCancels(t *testing.T) {
+	type Config struct {
+		Token string `conflata:"provider:token"`
+	}
+	provider := blockingProvider{release: make(chan struct{}), active: make(chan struct{}, 2)}
+	loader := New(WithProvider("aws", provider), WithSharedFetches())
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		wg   sync.WaitGroup
+		cfgs [2]Config
+		errs [2]error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = loader.Load(leaderCtx, &cfgs[0])
+	}()
+	<-provider.active
+	joined := newJoinSignalContext(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[1] = loader.Load(joined, &cfgs[1])
+	}()
+	select {
+	case <-joined.waiting:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second Load never joined the in-flight fetch")
+	}
+	cancel()
+	select {
+	case <-provider.active:
+	case <-time.After(2 * time.Second):
+		t.Fatal("live Load did not fetch again after the leader was cancelled")
+	}
+	close(provider.release)
+	wg.Wait()
+	if !errors.Is(errs[0], context.Canceled) {
+		t.Fatalf("expected cancelled Load to fail with context.Canceled, got %v", errs[0])
+	}
+	if errs[1] != nil || cfgs[1].Token != "token-value" {
+		t.Fatalf("live load: cfg=%+v err=%v", cfgs[1], errs[1])
+	}
+}
+
+// joinSignalContext closes waiting the first time Done is called. A Load only
+// waits on its context's Done channel while it has joined another Load's
+// in-flight fetch, so tests use it to know the join happened.
+type joinSignalContext struct {
+	context.Context
+	waiting chan struct{}
+	once    sync.Once
+}
+
+func newJoinSignalContext(parent context.Context) *joinSignalContext {
+	return &joinSignalContext{Context: parent, waiting: make(chan struct{})}
+}
+
+func (c *joinSignalContext) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.waiting) })
+	return c.Context.Done()
+}
+
+func TestSourcesForHonoursOrderAndDisabledSources(t *testing.T) {
+	loader := New(
+		WithSourceOrder(SourceProvider, SourceDefault, SourceEnv),
+		WithDisabledSources(SourceDefault),
+	)
+	tag := fieldTag{EnvKey: "FOO", ProviderKey: "bar", HasDefault: true}
+	var got []ValueSource
+	for _, src := range loader.sourcesFor(nil, tag) {
+		got = append(got, src.Source())
+	}
+	if want := []ValueSource{SourceProvider, SourceEnv}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("loader order = %v, want %v", got, want)
+	}
+
+	tag.Order = []ValueSource{SourceEnv, SourceDefault}
+	got = nil
+	for _, src := range loader.sourcesFor(nil, tag) {
+		got = append(got, src.Source())
+	}
+	if want := []ValueSource{SourceEnv}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("tag order = %v, want %v", got, want)
+	}
+}
+
+func TestBackendsForExpandsChains(t *testing.T) {
+	loader := New(
+		WithProviderChain("secrets", "vault", "AWS"),
+		WithDefaultProvider("secrets"),
+	)
+	for backend, want := range map[string][]string{
+		"":             {"vault", "aws"},
+		"gcp, secrets": {"gcp", "vault", "aws"},
+		"aws,secrets":  {"aws", "vault"},
+		"Vault":        {"vault"},
+	} {
+		got := loader.backendsFor(fieldTag{ProviderKey: "key", BackendName: backend})
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("backendsFor(%q) = %v, want %v", backend, got, want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package conflata
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -10,13 +12,47 @@ import (
 // fieldTag describes how to load and decode a single struct field.
 type fieldTag struct {
 	EnvKey       string
+	EnvAliases   []envAlias
 	ProviderKey  string
 	BackendName  string
 	Format       string
 	DefaultValue string
 	HasDefault   bool
+	Requirement  tagRequirement
+	Sensitive    bool
+	Order        []ValueSource
+	// EnvPrefix and ProviderPrefix are prepended to the keys of every field
+	// nested inside this one.
+	EnvPrefix      string
+	ProviderPrefix string
+	// Sep and KVSep select delimited decoding with custom separators.
+	Sep   string
+	KVSep string
+	// KeyEnv and KeyProvider name the separate sources of the private key for
+	// tls.Certificate fields.
+	KeyEnv      string
+	KeyProvider string
+	// Transforms names the stages applied to raw values before decoding.
+	Transforms []string
 }
 
+// envAlias is an additional environment variable consulted after EnvKey, in
+// tag order.
+type envAlias struct {
+	Name       string
+	Deprecated bool
+}
+
+// tagRequirement records whether a tag explicitly marked the field optional or
+// required. The zero value defers to the loader-wide default.
+type tagRequirement int
+
+const (
+	requirementUnset tagRequirement = iota
+	requirementOptional
+	requirementRequired
+)
+
 func parseFieldTag(raw string) (fieldTag, error) {
 	if raw == "" {
 		return fieldTag{}, nil
@@ -52,6 +88,13 @@ func parseFieldTag(raw string) (fieldTag, error) {
 		switch state {
 		case stateKey:
 			if unicode.IsSpace(r) {
+				rest := strings.TrimLeftFunc(raw[i:], unicode.IsSpace)
+				if keyBuilder.Len() != 0 && !strings.HasPrefix(rest, ":") {
+					if err := tag.assignFlag(keyBuilder.String()); err != nil {
+						return fieldTag{}, err
+					}
+					keyBuilder.Reset()
+				}
 				continue
 			}
 			if r == ':' {
@@ -110,7 +153,9 @@ func parseFieldTag(raw string) (fieldTag, error) {
 	switch state {
 	case stateKey:
 		if keyBuilder.Len() != 0 {
-			return fieldTag{}, fmt.Errorf("conflata: dangling key %q", keyBuilder.String())
+			if err := tag.assignFlag(keyBuilder.String()); err != nil {
+				return fieldTag{}, err
+			}
 		}
 	case statePreValue:
 		return fieldTag{}, fmt.Errorf("conflata: key %q missing value", currentKey)
@@ -122,14 +167,26 @@ func parseFieldTag(raw string) (fieldTag, error) {
 		return fieldTag{}, fmt.Errorf("conflata: unterminated quoted value for key %q", currentKey)
 	}
 
+	if (tag.Sep != "" || tag.KVSep != "") && tag.Format != "" && tag.Format != "csv" && tag.Format != "kv" {
+		return fieldTag{}, fmt.Errorf("conflata: sep and kvsep require format csv or kv, got %q", tag.Format)
+	}
 	return tag, nil
 }
 
+// decodeSpec returns the keys that control how the field's raw value is
+// decoded.
+func (t fieldTag) decodeSpec() decodeSpec {
+	return decodeSpec{format: t.Format, sep: t.Sep, kvsep: t.KVSep}
+}
+
 func (t *fieldTag) assign(key, value string) error {
 	switch key {
 	case "env":
-		t.EnvKey = value
+		return t.setEnvKeys(value)
 	case "provider":
+		if strings.HasSuffix(value, "#") {
+			return fmt.Errorf("conflata: provider key %q has an empty selector", value)
+		}
 		t.ProviderKey = value
 	case "backend":
 		t.BackendName = value
@@ -138,12 +195,176 @@ func (t *fieldTag) assign(key, value string) error {
 	case "default":
 		t.DefaultValue = value
 		t.HasDefault = true
+	case "optional", "required":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("conflata: key %q expects a boolean, got %q", key, value)
+		}
+		return t.setRequirement(key, enabled)
+	case "sensitive":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("conflata: key %q expects a boolean, got %q", key, value)
+		}
+		t.Sensitive = enabled
+	case "keyenv":
+		t.KeyEnv = value
+	case "keyprovider":
+		if strings.HasSuffix(value, "#") {
+			return fmt.Errorf("conflata: provider key %q has an empty selector", value)
+		}
+		t.KeyProvider = value
+	case "transform":
+		t.Transforms = nil
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				return fmt.Errorf("conflata: transform: empty stage in %q", value)
+			}
+			t.Transforms = append(t.Transforms, name)
+		}
+	case "sep":
+		t.Sep = value
+	case "kvsep":
+		t.KVSep = value
+	case "envprefix":
+		t.EnvPrefix = value
+	case "providerprefix":
+		t.ProviderPrefix = value
+	case "order":
+		order, err := parseSourceOrder(value)
+		if err != nil {
+			return err
+		}
+		t.Order = order
 	default:
 		return fmt.Errorf("unknown conflata tag key %q", key)
 	}
 	return nil
 }
 
+// scopeOnly reports whether the tag only sets key prefixes for nested fields
+// and names no source for the field itself.
+func (t fieldTag) scopeOnly() bool {
+	return (t.EnvPrefix != "" || t.ProviderPrefix != "") &&
+		t.EnvKey == "" && t.ProviderKey == "" && !t.HasDefault
+}
+
+// flagsOnly reports whether the tag sets nothing but the optional, required,
+// and sensitive flags. Such a tag annotates the field, typically a struct to
+// mask in Dump, without naming a source for it.
+func (t fieldTag) flagsOnly() bool {
+	stripped := t
+	stripped.Requirement = requirementUnset
+	stripped.Sensitive = false
+	return reflect.DeepEqual(stripped, fieldTag{})
+}
+
+// applyScope prepends the key prefixes inherited from enclosing fields.
+func (t *fieldTag) applyScope(scope walkScope) {
+	if t.EnvKey != "" {
+		t.EnvKey = scope.envPrefix + t.EnvKey
+	}
+	for i := range t.EnvAliases {
+		t.EnvAliases[i].Name = scope.envPrefix + t.EnvAliases[i].Name
+	}
+	if t.ProviderKey != "" {
+		t.ProviderKey = scope.providerPrefix + t.ProviderKey
+	}
+	if t.KeyEnv != "" {
+		t.KeyEnv = scope.envPrefix + t.KeyEnv
+	}
+	if t.KeyProvider != "" {
+		t.KeyProvider = scope.providerPrefix + t.KeyProvider
+	}
+}
+
+// keyTag returns the tag describing where the private key of a
+// tls.Certificate field is read from: the same backend and source order as the
+// certificate, with no default.
+func (t fieldTag) keyTag() fieldTag {
+	return fieldTag{
+		EnvKey:      t.KeyEnv,
+		ProviderKey: t.KeyProvider,
+		BackendName: t.BackendName,
+		Order:       t.Order,
+	}
+}
+
+// assignFlag handles keys written without a value, such as `optional` or
+// `sensitive`.
+func (t *fieldTag) assignFlag(raw string) error {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	switch key {
+	case "optional", "required":
+		return t.setRequirement(key, true)
+	case "sensitive":
+		t.Sensitive = true
+		return nil
+	default:
+		return fmt.Errorf("conflata: dangling key %q", raw)
+	}
+}
+
+// deprecatedSuffix marks an env alias as deprecated, as in
+// `env:NEW_NAME,OLD_NAME:deprecated`.
+const deprecatedSuffix = ":deprecated"
+
+// setEnvKeys parses a comma-separated list of environment variable names. The
+// first name is the canonical one; the rest are aliases.
+func (t *fieldTag) setEnvKeys(value string) error {
+	t.EnvAliases = nil
+	for i, part := range strings.Split(value, ",") {
+		name := strings.TrimSpace(part)
+		deprecated := false
+		if len(name) >= len(deprecatedSuffix) && strings.EqualFold(name[len(name)-len(deprecatedSuffix):], deprecatedSuffix) {
+			name = name[:len(name)-len(deprecatedSuffix)]
+			deprecated = true
+		}
+		switch {
+		case name == "":
+			return fmt.Errorf("conflata: env: empty variable name in %q", value)
+		case i == 0 && deprecated:
+			return fmt.Errorf("conflata: env: the first name %q cannot be deprecated", name)
+		case i == 0:
+			t.EnvKey = name
+		default:
+			t.EnvAliases = append(t.EnvAliases, envAlias{Name: name, Deprecated: deprecated})
+		}
+	}
+	return nil
+}
+
+// parseSourceOrder parses a comma-separated list such as "provider,env,default".
+func parseSourceOrder(value string) ([]ValueSource, error) {
+	var order []ValueSource
+	seen := make(map[ValueSource]bool)
+	for _, part := range strings.Split(value, ",") {
+		class := ValueSource(strings.ToLower(strings.TrimSpace(part)))
+		if !orderableSource(class) {
+			return nil, fmt.Errorf("conflata: order: unknown source %q", part)
+		}
+		if seen[class] {
+			return nil, fmt.Errorf("conflata: order: source %q listed twice", part)
+		}
+		seen[class] = true
+		order = append(order, class)
+	}
+	return order, nil
+}
+
+func (t *fieldTag) setRequirement(key string, enabled bool) error {
+	requirement := requirementRequired
+	if (key == "optional") == enabled {
+		requirement = requirementOptional
+	}
+	if t.Requirement != requirementUnset && t.Requirement != requirement {
+		return fmt.Errorf("conflata: optional and required are mutually exclusive")
+	}
+	t.Requirement = requirement
+	return nil
+}
+
 const (
 	stateKey = iota
 	statePreValue
@@ -1,6 +1,9 @@
 package conflata
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestParseFieldTagSuccess(t *testing.T) {
 	tag, err := parseFieldTag(`env:DATABASE_URL provider:db-url backend:vault format:json default:"fallback value"`)
@@ -42,3 +45,126 @@ func TestParseFieldTagMalformedComponent(t *testing.T) {
 		t.Fatal("expected error for malformed component")
 	}
 }
+
+func TestParseFieldTagRequirementFlags(t *testing.T) {
+	cases := map[string]tagRequirement{
+		`env:FOO optional`:         requirementOptional,
+		`required env:FOO`:         requirementRequired,
+		`env:FOO optional:false`:   requirementRequired,
+		`env:FOO required:"false"`: requirementOptional,
+		`env :FOO`:                 requirementUnset,
+	}
+	for raw, want := range cases {
+		tag, err := parseFieldTag(raw)
+		if err != nil {
+			t.Fatalf("parseFieldTag(%q) error: %v", raw, err)
+		}
+		if tag.Requirement != want || tag.EnvKey != "FOO" {
+			t.Fatalf("parseFieldTag(%q) = %+v, want requirement %v", raw, tag, want)
+		}
+	}
+}
+
+func TestParseFieldTagConflictingRequirement(t *testing.T) {
+	if _, err := parseFieldTag(`env:FOO optional required`); err == nil {
+		t.Fatal("expected error for optional and required together")
+	}
+	if _, err := parseFieldTag(`env:FOO optional:maybe`); err == nil {
+		t.Fatal("expected error for non-boolean optional value")
+	}
+}
+
+func TestParseFieldTagSensitive(t *testing.T) {
+	for raw, want := range map[string]bool{
+		`env:FOO sensitive`:       true,
+		`sensitive provider:foo`:  true,
+		`env:FOO sensitive:false`: false,
+	} {
+		tag, err := parseFieldTag(raw)
+		if err != nil {
+			t.Fatalf("parseFieldTag(%q) error: %v", raw, err)
+		}
+		if tag.Sensitive != want {
+			t.Fatalf("parseFieldTag(%q).Sensitive = %v, want %v", raw, tag.Sensitive, want)
+		}
+	}
+}
+
+func TestParseFieldTagOrder(t *testing.T) {
+	tag, err := parseFieldTag(`env:FOO provider:foo order:Provider,env,default`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []ValueSource{SourceProvider, SourceEnv, SourceDefault}
+	if !reflect.DeepEqual(tag.Order, want) {
+		t.Fatalf("Order = %v, want %v", tag.Order, want)
+	}
+	for _, raw := range []string{`env:FOO order:env,tag`, `env:FOO order:env,env`, `env:FOO order:decoder`} {
+		if _, err := parseFieldTag(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestParseFieldTagEnvAliases(t *testing.T) {
+	tag, err := parseFieldTag(`env:NEW_NAME,MID_NAME,OLD_NAME:deprecated`)
+	if err != nil {
+		t.Fatalf("parseFieldTag error: %v", err)
+	}
+	want := []envAlias{{Name: "MID_NAME"}, {Name: "OLD_NAME", Deprecated: true}}
+	if tag.EnvKey != "NEW_NAME" || !reflect.DeepEqual(tag.EnvAliases, want) {
+		t.Fatalf("unexpected env keys %q %+v", tag.EnvKey, tag.EnvAliases)
+	}
+	for _, raw := range []string{`env:NEW,`, `env:OLD:deprecated,NEW`, `env:,NEW`} {
+		if _, err := parseFieldTag(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestParseFieldTagPrefixes(t *testing.T) {
+	tag, err := parseFieldTag(`envprefix:PRIMARY_ providerprefix:prod/primary/`)
+	if err != nil {
+		t.Fatalf("parseFieldTag error: %v", err)
+	}
+	if tag.EnvPrefix != "PRIMARY_" || tag.ProviderPrefix != "prod/primary/" || !tag.scopeOnly() {
+		t.Fatalf("unexpected prefixes %+v", tag)
+	}
+	tag.ProviderKey = "settings"
+	if tag.scopeOnly() {
+		t.Fatal("expected tag with a provider key not to be scope-only")
+	}
+}
+
+func TestParseFieldTagFlagsOnly(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"sensitive":                true,
+		"optional sensitive":       true,
+		"required:false":           true,
+		"env:PORT sensitive":       false,
+		"format:json optional":     false,
+		"envprefix:APP_ sensitive": false,
+		"default:5 sensitive":      false,
+	} {
+		tag, err := parseFieldTag(raw)
+		if err != nil {
+			t.Fatalf("parseFieldTag(%q) error: %v", raw, err)
+		}
+		if got := tag.flagsOnly(); got != want {
+			t.Fatalf("flagsOnly(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestParseFieldTagSeparators(t *testing.T) {
+	tag, err := parseFieldTag(`env:LABELS sep:";" kvsep::`)
+	if err != nil {
+		t.Fatalf("parseFieldTag error: %v", err)
+	}
+	if tag.Sep != ";" || tag.KVSep != ":" {
+		t.Fatalf("unexpected separators %q %q", tag.Sep, tag.KVSep)
+	}
+	if _, err := parseFieldTag(`env:HOSTS sep:; format:json`); err == nil {
+		t.Fatal("expected error for sep with a non-delimited format")
+	}
+}
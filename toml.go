@@ -0,0 +1,70 @@
+package conflata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// decodeTOML decodes raw with github.com/BurntSushi/toml. Types that describe
+// their fields with json tags but no toml tags are decoded through JSON so the
+// json tags apply; their type errors name the field instead of the line.
+func decodeTOML(raw string, targetType reflect.Type) (any, error) {
+	holder := reflect.New(targetType)
+	if usesTag(targetType, "toml") || !usesTag(targetType, "json") {
+		if _, err := toml.Decode(raw, holder.Interface()); err != nil {
+			return nil, fmt.Errorf("toml decode: %w", err)
+		}
+		return holder.Elem().Interface(), nil
+	}
+
+	var doc map[string]any
+	if _, err := toml.Decode(raw, &doc); err != nil {
+		return nil, fmt.Errorf("toml decode: %w", err)
+	}
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("toml decode: %w", err)
+	}
+	if err := json.Unmarshal(payload, holder.Interface()); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			// Offsets refer to the intermediate JSON, so report the field.
+			kind := strings.Fields(typeErr.Value + " value")[0]
+			return nil, fmt.Errorf("toml decode: field %s: TOML %s cannot be stored in %s", typeErr.Field, kind, typeErr.Type)
+		}
+		return nil, fmt.Errorf("toml decode: %w", err)
+	}
+	return holder.Elem().Interface(), nil
+}
+
+// usesTag reports whether any struct field reachable from t carries a tag with
+// the given key.
+func usesTag(t reflect.Type, key string) bool {
+	return reachesTag(t, key, make(map[reflect.Type]bool))
+}
+
+func reachesTag(t reflect.Type, key string, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if holder, ok := newSecretHolder(t); ok {
+		t = holder.secretType()
+		return reachesTag(t, key, seen)
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup(key); ok || reachesTag(f.Type, key, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package conflata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	type Native struct {
+		Host     string         `toml:"host_name"`
+		Timeout  time.Duration  `toml:"timeout"`
+		Password Secret[string] `toml:"password"`
+	}
+	got, err := decodeTOML("host_name = \"db\"\ntimeout = \"5s\"\npassword = \"s3cr3t\"\n", reflect.TypeOf(Native{}))
+	if err != nil {
+		t.Fatalf("decodeTOML error: %v", err)
+	}
+	if native := got.(Native); native.Host != "db" || native.Timeout != 5*time.Second || native.Password.Reveal() != "s3cr3t" {
+		t.Fatalf("unexpected native %+v", native)
+	}
+
+	type JSONTagged struct {
+		Host  string `json:"host_name"`
+		Ports []int  `json:"ports"`
+		Inner struct {
+			Enabled bool `json:"enabled"`
+		} `json:"inner"`
+	}
+	got, err = decodeTOML("host_name = \"db\"\nports = [1, 2]\n[inner]\nenabled = true\n", reflect.TypeOf(JSONTagged{}))
+	if err != nil {
+		t.Fatalf("decodeTOML error: %v", err)
+	}
+	if tagged := got.(JSONTagged); tagged.Host != "db" || len(tagged.Ports) != 2 || !tagged.Inner.Enabled {
+		t.Fatalf("unexpected json-tagged %+v", tagged)
+	}
+
+	_, err = decodeTOML("host_name = 42\n", reflect.TypeOf(JSONTagged{}))
+	if err == nil || !strings.Contains(err.Error(), "field host_name") || strings.Contains(err.Error(), "42") {
+		t.Fatalf("expected field-level type error without the value, got %v", err)
+	}
+}
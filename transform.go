@@ -0,0 +1,107 @@
+package conflata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TransformFunc rewrites a raw value before it is decoded. Transforms named in
+// a field's `transform:` key run in order on the value from whichever source
+// resolved it.
+type TransformFunc func(raw string) (string, error)
+
+var builtinTransforms = map[string]TransformFunc{
+	"base64":    transformBase64(base64.StdEncoding, base64.RawStdEncoding),
+	"base64url": transformBase64(base64.URLEncoding, base64.RawURLEncoding),
+	"hex":       transformHex,
+	"gunzip":    transformGunzip,
+	"trimspace": transformTrimSpace,
+	"unquote":   transformUnquote,
+}
+
+// transformBase64 decodes padded or unpadded base64, ignoring surrounding
+// whitespace and line breaks.
+func transformBase64(padded, raw *base64.Encoding) TransformFunc {
+	return func(value string) (string, error) {
+		value = strings.Join(strings.Fields(value), "")
+		enc := padded
+		if !strings.HasSuffix(value, "=") {
+			enc = raw
+		}
+		out, err := enc.DecodeString(value)
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+}
+
+func transformHex(value string) (string, error) {
+	out, err := hex.DecodeString(strings.TrimSpace(value))
+	var invalid hex.InvalidByteError
+	if errors.As(err, &invalid) {
+		// The error quotes the offending character.
+		return "", errors.New("invalid hex character")
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func transformGunzip(value string) (string, error) {
+	reader, err := gzip.NewReader(strings.NewReader(value))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	var out bytes.Buffer
+	if _, err := io.Copy(&out, reader); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func transformTrimSpace(value string) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+func transformUnquote(value string) (string, error) {
+	out, err := strconv.Unquote(strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("value is not a quoted Go string: %w", err)
+	}
+	return out, nil
+}
+
+// pipeline returns a function that runs the named transforms in order, or nil
+// when names is empty. Errors name the failing stage.
+func (l *Loader) pipeline(names []string) func(string) (string, error) {
+	if len(names) == 0 {
+		return nil
+	}
+	return func(raw string) (string, error) {
+		for _, name := range names {
+			fn, ok := l.transforms[name]
+			if !ok {
+				return "", fmt.Errorf("%s: unknown transform", name)
+			}
+			out, err := fn(raw)
+			if err != nil {
+				if !l.verboseDecodeErrors {
+					err = errors.New(scrubRaw(err.Error(), raw))
+				}
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+			raw = out
+		}
+		return raw, nil
+	}
+}
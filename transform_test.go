@@ -0,0 +1,82 @@
+package conflata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTransforms(t *testing.T) {
+	for _, tc := range []struct {
+		name, in, want string
+	}{
+		{"base64", "aGVsbG8=\n", "hello"},
+		{"base64", "aGVsbG8", "hello"},
+		{"base64url", "Pz8_", "???"},
+		{"hex", "68656c6c6f", "hello"},
+		{"trimspace", "  hello\n", "hello"},
+		{"unquote", `"line\nbreak"`, "line\nbreak"},
+	} {
+		got, err := builtinTransforms[tc.name](tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("%s(%q) = %q, %v; want %q", tc.name, tc.in, got, err, tc.want)
+		}
+	}
+	if _, err := transformHex("zz"); err == nil || strings.Contains(err.Error(), "z") {
+		t.Fatalf("expected hex error without the input, got %v", err)
+	}
+}
+
+func TestLoaderTransformPipeline(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write([]byte(`{"name":"bundle"}`))
+	_ = zw.Close()
+	encoded := base64.StdEncoding.EncodeToString(compressed.Bytes())
+
+	type Bundle struct {
+		Name string `json:"name"`
+	}
+	type Config struct {
+		Bundle Bundle `conflata:"env:BUNDLE transform:base64,gunzip"`
+		Token  string `conflata:"env:TOKEN transform:reverse,trimspace"`
+		Broken string `conflata:"env:BROKEN default:plain transform:base64,gunzip"`
+	}
+	env := map[string]string{"BUNDLE": encoded, "TOKEN": " cba ", "BROKEN": "bm90LWd6aXA="}
+	loader := New(
+		WithEnvLookup(func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}),
+		WithTransform("reverse", func(raw string) (string, error) {
+			runes := []rune(raw)
+			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			return string(runes), nil
+		}),
+	)
+	var cfg Config
+	report, err := loader.LoadWithReport(context.Background(), &cfg)
+	if cfg.Bundle.Name != "bundle" || cfg.Token != "abc" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	var group *ErrorGroup
+	if !errors.As(err, &group) || len(group.Fields()) != 1 {
+		t.Fatalf("expected Broken to fail, got %v", err)
+	}
+	attempts := group.Fields()[0].Attempts
+	if len(attempts) != 2 || attempts[0].Source != SourceTransform || attempts[1].Source != SourceTransform {
+		t.Fatalf("expected one transform attempt per source, got %+v", attempts)
+	}
+	if !strings.HasPrefix(attempts[0].Err.Error(), "gunzip: ") || !errors.Is(attempts[0].Err, ErrDecode) {
+		t.Fatalf("expected failing stage to be named, got %v", attempts[0].Err)
+	}
+	if entry, _ := report.Field("Token"); strings.Join(entry.Transforms, ",") != "reverse,trimspace" {
+		t.Fatalf("unexpected report %+v", entry)
+	}
+}
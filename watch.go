@@ -0,0 +1,106 @@
+package conflata
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Watcher is implemented by providers that can report when the value behind a
+// key changes, such as a rotated secret. Watch delivers changed keys on the
+// returned channel until ctx is done and then closes it. Keys are the
+// decorated keys the Loader passes to Fetch. Providers that can only watch in
+// some configurations, such as wrappers around another provider, return
+// errors.ErrUnsupported when watching is not possible.
+//
+// Live.Watch with WatchProviders subscribes to every registered Watcher and
+// re-resolves only the fields that depend on a changed key.
+type Watcher interface {
+	Watch(ctx context.Context, keys []string) (<-chan string, error)
+}
+
+// VersionFunc reports the current version of the value behind key. Any string
+// that changes when the value changes will do, such as a version ID or a
+// modification time. Errors wrapping ErrNotFound mark the value as absent;
+// other errors are ignored until the next poll.
+type VersionFunc func(ctx context.Context, key string) (string, error)
+
+// PollVersions implements Watcher.Watch for providers that can cheaply look up
+// a version for each key. It calls version for every key once per interval and
+// sends the key whenever its version changes or it appears or disappears. The
+// first poll happens immediately and only records a baseline. The returned
+// channel is closed once ctx is done.
+func PollVersions(ctx context.Context, keys []string, interval time.Duration, version VersionFunc) <-chan string {
+	changes := make(chan string)
+	go func() {
+		defer close(changes)
+		type state struct {
+			version string
+			present bool
+		}
+		known := make(map[string]state, len(keys))
+		poll := func(baseline bool) bool {
+			for _, key := range keys {
+				v, err := version(ctx, key)
+				if ctx.Err() != nil {
+					return false
+				}
+				var current state
+				switch {
+				case err == nil:
+					current = state{version: v, present: true}
+				case errors.Is(err, ErrNotFound):
+				default:
+					continue
+				}
+				previous, seen := known[key]
+				known[key] = current
+				if baseline || !seen || previous == current {
+					continue
+				}
+				select {
+				case changes <- key:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+		if !poll(true) {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !poll(false) {
+					return
+				}
+			}
+		}
+	}()
+	return changes
+}
+
+// fieldDependency records that the field at path is resolved from a provider
+// key.
+type fieldDependency struct {
+	path string
+	key  fetchKey
+}
+
+func dependenciesOf(fieldPath string, sources []valueSource) []fieldDependency {
+	var deps []fieldDependency
+	for _, src := range sources {
+		if ps, ok := src.(providerSource); ok && ps.key != "" {
+			deps = append(deps, fieldDependency{
+				path: fieldPath,
+				key:  fetchKey{backend: ps.backend, key: ps.key},
+			})
+		}
+	}
+	return deps
+}
@@ -0,0 +1,59 @@
+package conflata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPollVersionsReportsChanges(t *testing.T) {
+	var mu sync.Mutex
+	versions := map[string]string{"a": "1", "b": "1"}
+	// The baseline poll reads both keys; it is complete after the second call.
+	baseline := make(chan struct{})
+	calls := 0
+	version := func(_ context.Context, key string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 2 {
+			close(baseline)
+		}
+		v, ok := versions[key]
+		if !ok {
+			return "", fmt.Errorf("%s: %w", key, ErrNotFound)
+		}
+		return v, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := PollVersions(ctx, []string{"a", "b"}, 5*time.Millisecond, version)
+	<-baseline
+
+	mu.Lock()
+	versions["b"] = "2"
+	mu.Unlock()
+	expectKey(t, changes, "b")
+
+	mu.Lock()
+	delete(versions, "a")
+	mu.Unlock()
+	expectKey(t, changes, "a")
+
+	cancel()
+	for range changes {
+	}
+}
+
+func expectKey(t *testing.T, changes <-chan string, want string) {
+	t.Helper()
+	select {
+	case key := <-changes:
+		if key != want {
+			t.Fatalf("expected %q, got %q", want, key)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %q", want)
+	}
+}
@@ -0,0 +1,121 @@
+package conflata
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// decodeYAML decodes raw with gopkg.in/yaml.v3. Struct fields without a yaml
+// tag are also matched by their json tag, so structs written for the json
+// format decode unchanged.
+func decodeYAML(raw string, targetType reflect.Type) (any, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &root); err != nil {
+		return nil, fmt.Errorf("yaml decode: %w", err)
+	}
+	renameYAMLKeys(&root, targetType)
+	holder := reflect.New(targetType)
+	if err := root.Decode(holder.Interface()); err != nil {
+		return nil, fmt.Errorf("yaml decode: %w", err)
+	}
+	return holder.Elem().Interface(), nil
+}
+
+// renameYAMLKeys rewrites mapping keys in node that name a struct field of t by
+// its json tag to the key yaml.v3 expects for that field. Nodes keep their
+// positions, so decode errors still point at the original line.
+func renameYAMLKeys(node *yaml.Node, t reflect.Type) {
+	for node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if holder, ok := newSecretHolder(t); ok {
+		renameYAMLKeys(node, holder.secretType())
+		return
+	}
+	if reflect.PointerTo(t).Implements(yamlUnmarshalerType) || implementsUnmarshaler(t) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if field, ok := fields[node.Content[i].Value]; ok {
+				node.Content[i].Value = field.key
+				renameYAMLKeys(node.Content[i+1], field.typ)
+			}
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			renameYAMLKeys(node.Content[i], t.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, item := range node.Content {
+			renameYAMLKeys(item, t.Elem())
+		}
+	}
+}
+
+type yamlField struct {
+	key string
+	typ reflect.Type
+}
+
+// yamlFields maps every key that may name a field of the struct type t to the
+// key yaml.v3 decodes that field from: its yaml tag, otherwise its json tag or
+// field name, both of which yaml.v3 would only match lower-cased.
+func yamlFields(t reflect.Type) map[string]yamlField {
+	fields := make(map[string]yamlField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if tag, ok := f.Tag.Lookup("yaml"); ok {
+			name, opts, _ := strings.Cut(tag, ",")
+			switch {
+			case name == "-":
+			case strings.Contains(opts, "inline") && f.Type.Kind() == reflect.Struct:
+				for key, field := range yamlFields(f.Type) {
+					fields[key] = field
+				}
+			default:
+				if name == "" {
+					name = strings.ToLower(f.Name)
+				}
+				fields[name] = yamlField{key: name, typ: f.Type}
+			}
+			continue
+		}
+		key := strings.ToLower(f.Name)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			fields[f.Name] = yamlField{key: key, typ: f.Type}
+		default:
+			fields[name] = yamlField{key: key, typ: f.Type}
+		}
+		if _, taken := fields[key]; !taken {
+			fields[key] = yamlField{key: key, typ: f.Type}
+		}
+	}
+	return fields
+}
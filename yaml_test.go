@@ -0,0 +1,66 @@
+package conflata
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeYAMLHonoursTags(t *testing.T) {
+	type Pool struct {
+		MaxOpen int           `json:"max_open"`
+		Idle    time.Duration `yaml:"idle_timeout"`
+	}
+	type Database struct {
+		Host     string         `json:"host_name"`
+		Port     int            `yaml:"db_port"`
+		Password Secret[string] `json:"password"`
+		Replicas []Pool         `json:"replicas"`
+		Labels   map[string]Pool
+	}
+	raw := `
+host_name: db.internal
+db_port: 5432
+password: s3cr3t
+replicas:
+  - max_open: 4
+labels:
+  eu: {max_open: 2, idle_timeout: 30s}
+`
+	got, err := decodeYAML(raw, reflect.TypeOf(Database{}))
+	if err != nil {
+		t.Fatalf("decodeYAML error: %v", err)
+	}
+	db := got.(Database)
+	if db.Host != "db.internal" || db.Port != 5432 || db.Password.Reveal() != "s3cr3t" {
+		t.Fatalf("unexpected database %+v", db)
+	}
+	if len(db.Replicas) != 1 || db.Replicas[0].MaxOpen != 4 {
+		t.Fatalf("unexpected replicas %+v", db.Replicas)
+	}
+	if eu := db.Labels["eu"]; eu.MaxOpen != 2 || eu.Idle != 30*time.Second {
+		t.Fatalf("unexpected labels %+v", db.Labels)
+	}
+}
+
+func TestLoaderYAMLDefaultFormat(t *testing.T) {
+	type Settings struct {
+		Endpoint string `json:"endpoint"`
+		Retries  int    `json:"retries"`
+	}
+	type Config struct {
+		Settings Settings `conflata:"env:SETTINGS"`
+	}
+	loader := New(
+		WithEnvLookup(func(string) (string, bool) { return "endpoint: https://api\nretries: 3\n", true }),
+		WithDefaultFormat("yaml"),
+	)
+	var cfg Config
+	if err := loader.Load(context.Background(), &cfg); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Settings != (Settings{Endpoint: "https://api", Retries: 3}) {
+		t.Fatalf("unexpected settings %+v", cfg.Settings)
+	}
+}